@@ -0,0 +1,300 @@
+// Package bot provides an event driven framework for reddit bots built on top of mira streams.
+//
+// Register handlers for the events your bot is interested in, then call Run. The bot starts the needed
+// streams, dispatches their items to a pool of workers and recovers from panicking handlers:
+//
+//	b := bot.New(reddit, bot.Options{Subreddits: []string{"pics", "aww"}})
+//	b.Handle(bot.NewPost, "", func(ctx context.Context, e *bot.Event) error {
+//		fmt.Println("New post:", e.Submission.GetTitle())
+//		return nil
+//	})
+//	b.Run(ctx)
+//
+// # Ordering
+//
+// Events of the same thread (a post and its comments, or a modmail conversation) are always handled by
+// the same worker, one after another & in the order they were received. Events of different threads
+// are handled in parallel. A handler that exceeds Options.HandlerTimeout keeps its thread blocked until it
+// returned, so handlers should stop once their context is done.
+//
+// # Concurrency
+//
+// Handlers run in parallel, but queued calls on a mira.Reddit (reddit.Comment(id).Reply(...)) are not
+// safe for concurrent use: Two handlers could pick up each other's queued object. Handlers must only use
+// the calls that take the object as argument, e.g. ReplyWithID, SubmissionInfoID or GetModMailByID.
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ttgmpsn/mira"
+)
+
+// HandlerFunc handles a single event. The context is cancelled once Options.HandlerTimeout is reached
+// or the context passed to Run is cancelled.
+// Handlers run in parallel, so they must not use queued calls on a mira.Reddit, see "Concurrency" above.
+type HandlerFunc func(ctx context.Context, e *Event) error
+
+// ErrorFunc is called for each error that happens while the bot is running. For errors that
+// don't belong to an event (i.e. a stream failing), e is nil.
+type ErrorFunc func(e *Event, err error)
+
+// Options configures a Bot. All fields are optional except Subreddits, which is needed
+// for every event type except InboxMention.
+type Options struct {
+	// Subreddits to watch.
+	Subreddits []string
+	// Workers is the number of events handled in parallel. Defaults to 4.
+	Workers int
+	// QueueSize is the number of events each worker can queue up. Defaults to 100.
+	QueueSize int
+	// HandlerTimeout is the time after which the context of a handler is cancelled. Zero means no limit.
+	// The next event of the same thread is only handled once the handler has returned.
+	HandlerTimeout time.Duration
+	// RestartDelay is the time to wait before restarting a failed stream. Defaults to one minute.
+	RestartDelay time.Duration
+	// ModMailState filters the modmail conversations to watch. Defaults to mira.ModMailAll.
+	ModMailState mira.ModMailState
+	// OnError gets called for every error. Defaults to ignoring errors.
+	OnError ErrorFunc
+}
+
+// Bot dispatches events from mira streams to registered handlers.
+// While the bot is running, only use the mira.Reddit from handlers as described in "Concurrency" above.
+type Bot struct {
+	r    *mira.Reddit
+	opts Options
+
+	mu       sync.Mutex // guards handlers
+	handlers map[EventType][]handler
+
+	startMu sync.Mutex // streams are started one at a time, see start()
+}
+
+type handler struct {
+	subreddit string
+	f         HandlerFunc
+}
+
+// New creates a Bot using the given reddit instance, which should already be authenticated.
+func New(r *mira.Reddit, opts Options) *Bot {
+	if opts.Workers < 1 {
+		opts.Workers = 4
+	}
+	if opts.QueueSize < 1 {
+		opts.QueueSize = 100
+	}
+	if opts.RestartDelay <= 0 {
+		opts.RestartDelay = time.Minute
+	}
+	if opts.ModMailState == "" {
+		opts.ModMailState = mira.ModMailAll
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(*Event, error) {}
+	}
+	return &Bot{
+		r:        r,
+		opts:     opts,
+		handlers: make(map[EventType][]handler),
+	}
+}
+
+// Handle registers f for all events of type t in subreddit. Pass an empty subreddit to receive
+// the events of all subreddits. Handlers should be registered before calling Run.
+func (b *Bot) Handle(t EventType, subreddit string, f HandlerFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[t] = append(b.handlers[t], handler{subreddit: subreddit, f: f})
+}
+
+// Run starts all streams needed by the registered handlers and dispatches their events
+// until ctx is cancelled. It then stops the streams, waits for all queued events to be
+// handled & returns. Handlers get a context derived from ctx, so they can stop early on shutdown.
+func (b *Bot) Run(ctx context.Context) error {
+	b.mu.Lock()
+	types := []EventType{}
+	for t := range b.handlers {
+		if t != InboxMention && len(b.opts.Subreddits) == 0 {
+			b.mu.Unlock()
+			return fmt.Errorf("no subreddits set to watch for %s events", t)
+		}
+		types = append(types, t)
+	}
+	b.mu.Unlock()
+	if len(types) == 0 {
+		return errors.New("no handlers registered")
+	}
+
+	queues := make([]chan *Event, b.opts.Workers)
+	var workers sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan *Event, b.opts.QueueSize)
+		workers.Add(1)
+		go func(q <-chan *Event) {
+			defer workers.Done()
+			for e := range q {
+				b.handle(ctx, e)
+			}
+		}(queues[i])
+	}
+	dispatch := func(e *Event) {
+		h := fnv.New32a()
+		h.Write([]byte(e.Thread()))
+		queues[h.Sum32()%uint32(len(queues))] <- e
+	}
+
+	var streams sync.WaitGroup
+	for _, t := range types {
+		streams.Add(1)
+		go func(t EventType) {
+			defer streams.Done()
+			b.forward(ctx, t, dispatch)
+		}(t)
+	}
+
+	streams.Wait()
+	for _, q := range queues {
+		close(q)
+	}
+	workers.Wait()
+	return nil
+}
+
+// forward (re)starts the stream for t and dispatches its events until ctx is cancelled.
+func (b *Bot) forward(ctx context.Context, t EventType, dispatch func(*Event)) {
+	for {
+		events, closeC, err := b.start(t)
+		if err != nil {
+			b.opts.OnError(nil, fmt.Errorf("couldn't start %s stream: %w", t, err))
+		} else {
+			err = pump(ctx, events, dispatch)
+			close(closeC)
+			if err != nil {
+				b.opts.OnError(nil, fmt.Errorf("%s stream: %w", t, err))
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.opts.RestartDelay):
+		}
+	}
+}
+
+// pump dispatches events until ctx is cancelled (returning nil) or the stream closes.
+func pump(ctx context.Context, events <-chan *Event, dispatch func(*Event)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				return errors.New("stream was closed")
+			}
+			dispatch(e)
+		}
+	}
+}
+
+// start starts the stream for t. Queued calls on a mira.Reddit must not be interleaved,
+// so only one stream is started at a time.
+func (b *Bot) start(t EventType) (<-chan *Event, chan struct{}, error) {
+	b.startMu.Lock()
+	defer b.startMu.Unlock()
+
+	if t == ModMailMessage {
+		s, err := b.r.Subreddit(b.opts.Subreddits...).StreamModMail(b.opts.ModMailState)
+		if err != nil {
+			return nil, nil, err
+		}
+		out := make(chan *Event)
+		go func() {
+			defer close(out)
+			for e := range s.C {
+				select {
+				case out <- newModMailEvent(e):
+				case <-s.Close:
+					return
+				}
+			}
+		}()
+		return out, s.Close, nil
+	}
+
+	var s *mira.SubmissionStream
+	var err error
+	switch t {
+	case NewPost:
+		s, err = b.r.Subreddit(b.opts.Subreddits...).StreamPosts()
+	case NewComment:
+		s, err = b.r.Subreddit(b.opts.Subreddits...).StreamComments()
+	case ModQueueItem:
+		s, err = b.r.Subreddit(b.opts.Subreddits...).StreamModQueue()
+	case InboxMention:
+		s, err = b.r.Me().StreamMentions()
+	default:
+		return nil, nil, fmt.Errorf("unknown event type %d", t)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	out := make(chan *Event)
+	go func() {
+		defer close(out)
+		for sub := range s.C {
+			select {
+			case out <- newSubmissionEvent(t, sub):
+			case <-s.Close:
+				return
+			}
+		}
+	}()
+	return out, s.Close, nil
+}
+
+// handle calls all handlers matching e.
+func (b *Bot) handle(ctx context.Context, e *Event) {
+	b.mu.Lock()
+	handlers := append([]handler{}, b.handlers[e.Type]...)
+	b.mu.Unlock()
+
+	for _, h := range handlers {
+		if h.subreddit != "" && !strings.EqualFold(h.subreddit, e.Subreddit) {
+			continue
+		}
+		if err := b.call(ctx, h.f, e); err != nil {
+			b.opts.OnError(e, err)
+		}
+	}
+}
+
+// call runs f with a context derived from parent, recovering from panics. If the handler timeout is
+// reached, the context of f is cancelled, but call still waits for f to return so events of the same
+// thread stay in order.
+func (b *Bot) call(parent context.Context, f HandlerFunc, e *Event) (err error) {
+	ctx := parent
+	if b.opts.HandlerTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.opts.HandlerTimeout)
+		defer cancel()
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("handler panicked: %v", p)
+		}
+	}()
+	err = f(ctx, e)
+	// Shutting down isn't an error of the handler, only exceeding its own timeout is.
+	if err == nil && ctx.Err() != nil && parent.Err() == nil {
+		err = fmt.Errorf("handler for %s %s: %w", e.Type, e.Thread(), ctx.Err())
+	}
+	return err
+}
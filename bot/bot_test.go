@@ -0,0 +1,36 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCallContext(t *testing.T) {
+	b := New(nil, Options{HandlerTimeout: 50 * time.Millisecond})
+	e := &Event{Type: NewPost}
+	wait := func(ctx context.Context, e *Event) error {
+		<-ctx.Done()
+		return nil
+	}
+
+	// Exceeding the handler timeout is reported.
+	if err := b.call(context.Background(), wait, e); err == nil {
+		t.Error("got no error for a handler exceeding its timeout")
+	}
+
+	// Cancelling the parent reaches running handlers & isn't reported.
+	parent, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	b.opts.HandlerTimeout = 0
+	done := make(chan error)
+	go func() { done <- b.call(parent, wait, e) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("got %v for a handler stopped by shutdown", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelling the parent context didn't reach the handler")
+	}
+}
@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"github.com/ttgmpsn/mira"
+	"github.com/ttgmpsn/mira/models"
+)
+
+// EventType defines what a bot reacts to.
+type EventType int
+
+// List of all events a Bot can handle
+const (
+	// NewPost is a new post in a watched subreddit.
+	NewPost EventType = iota
+	// NewComment is a new comment in a watched subreddit.
+	NewComment
+	// ModQueueItem is a new post or comment in the mod queue of a watched subreddit.
+	ModQueueItem
+	// ModMailMessage is a new modmail conversation or message in a watched subreddit.
+	ModMailMessage
+	// InboxMention is a username mention of the logged in user.
+	InboxMention
+)
+
+func (t EventType) String() string {
+	switch t {
+	case NewPost:
+		return "NewPost"
+	case NewComment:
+		return "NewComment"
+	case ModQueueItem:
+		return "ModQueueItem"
+	case ModMailMessage:
+		return "ModMailMessage"
+	case InboxMention:
+		return "InboxMention"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event is passed to handlers. Depending on Type, either Submission or ModMail is set.
+type Event struct {
+	Type       EventType
+	Subreddit  string
+	Submission models.Submission
+	ModMail    *mira.ModMailEvent
+}
+
+// Thread returns an identifier for the thread the event belongs to: the post for posts & comments,
+// the conversation for modmail. Events of the same thread are handled in order.
+func (e *Event) Thread() string {
+	if e.ModMail != nil {
+		return e.ModMail.Conversation.Conversation.ID
+	}
+	if c, ok := e.Submission.(*models.Comment); ok && c.LinkID != "" {
+		return string(c.LinkID)
+	}
+	return string(e.Submission.GetID())
+}
+
+func newSubmissionEvent(t EventType, s models.Submission) *Event {
+	return &Event{
+		Type:       t,
+		Subreddit:  s.GetSubreddit(),
+		Submission: s,
+	}
+}
+
+func newModMailEvent(e *mira.ModMailEvent) *Event {
+	return &Event{
+		Type:      ModMailMessage,
+		Subreddit: e.Conversation.Conversation.Owner.DisplayName,
+		ModMail:   e,
+	}
+}
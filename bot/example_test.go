@@ -0,0 +1,56 @@
+package bot_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/ttgmpsn/mira"
+	"github.com/ttgmpsn/mira/bot"
+)
+
+func Example() {
+	// Initialize reddit instance like usually - see mira examples.
+	reddit := mira.Init(mira.Credentials{})
+
+	b := bot.New(reddit, bot.Options{
+		Subreddits:     []string{"pics", "aww"},
+		Workers:        8,
+		HandlerTimeout: 30 * time.Second,
+		OnError: func(e *bot.Event, err error) {
+			fmt.Println("Error:", err)
+		},
+	})
+
+	// Handle new posts in all watched subreddits.
+	b.Handle(bot.NewPost, "", func(ctx context.Context, e *bot.Event) error {
+		fmt.Println("New post:", e.Submission.GetTitle())
+		return nil
+	})
+
+	// Only handle comments in r/pics.
+	b.Handle(bot.NewComment, "pics", func(ctx context.Context, e *bot.Event) error {
+		if strings.Contains(e.Submission.GetBody(), "!help") {
+			// Handlers run in parallel, so use the calls taking the ID instead of queueing it.
+			_, err := reddit.ReplyWithID(string(e.Submission.GetID()), "How can I help?")
+			return err
+		}
+		return nil
+	})
+
+	// Handle modmail of all watched subreddits.
+	b.Handle(bot.ModMailMessage, "", func(ctx context.Context, e *bot.Event) error {
+		fmt.Println("New modmail message by", e.ModMail.Message.Author.Name)
+		return nil
+	})
+
+	// Stop the bot gracefully on Ctrl+C.
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	if err := b.Run(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,98 @@
+package mira
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// Checkpointer stores how far a stream has gotten, so it can resume where it left off after a restart.
+// Set one via reddit.Config.Checkpointer to enable it for all streams.
+//
+// Each stream uses its own key, e.g. "posts:pics" or "modmail:pics:all". Checkpoints are either
+// the RedditID of the newest processed item or, for modmail, a RFC 3339 timestamp.
+type Checkpointer interface {
+	// Load returns the checkpoint of a stream, or an empty string if there is none yet.
+	Load(stream string) (string, error)
+	// Save stores the checkpoint of a stream.
+	Save(stream, checkpoint string) error
+}
+
+// MemoryCheckpointer keeps checkpoints in memory. It is mainly useful for testing and to share
+// checkpoints between streams that are restarted within the same process.
+type MemoryCheckpointer struct {
+	mu sync.Mutex // guards m
+	m  map[string]string
+}
+
+// NewMemoryCheckpointer creates an empty MemoryCheckpointer.
+func NewMemoryCheckpointer() *MemoryCheckpointer {
+	return &MemoryCheckpointer{m: make(map[string]string)}
+}
+
+// Load returns the checkpoint of a stream.
+func (m *MemoryCheckpointer) Load(stream string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.m[stream], nil
+}
+
+// Save stores the checkpoint of a stream.
+func (m *MemoryCheckpointer) Save(stream, checkpoint string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.m[stream] = checkpoint
+	return nil
+}
+
+// FileCheckpointer keeps checkpoints in a JSON file. The whole file is rewritten on each Save.
+type FileCheckpointer struct {
+	mu   sync.Mutex // guards m & the file
+	path string
+	m    map[string]string
+}
+
+// NewFileCheckpointer creates a FileCheckpointer storing to path.
+// Existing checkpoints are read from the file if it exists.
+func NewFileCheckpointer(path string) (*FileCheckpointer, error) {
+	f := &FileCheckpointer{
+		path: path,
+		m:    make(map[string]string),
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &f.m); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Load returns the checkpoint of a stream.
+func (f *FileCheckpointer) Load(stream string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.m[stream], nil
+}
+
+// Save stores the checkpoint of a stream and writes all checkpoints to disk.
+func (f *FileCheckpointer) Save(stream, checkpoint string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.m[stream] = checkpoint
+	data, err := json.Marshal(f.m)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so a crash never leaves a half written file behind.
+	tmp := f.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
@@ -175,3 +175,80 @@ func ExampleReddit_StreamPosts() {
 		}
 	}()
 }
+
+func ExampleReddit_StreamModMail() {
+	// Initialize reddit instance like usually - see other examples.
+	reddit := mira.Init(mira.Credentials{})
+
+	// Create stream for all subreddits you moderate. Use Subreddit() instead of Me() to only watch some subreddits.
+	stream, err := reddit.Me().StreamModMail(mira.ModMailAll)
+	if err != nil {
+		panic(err)
+	}
+
+	// Create listener
+	go func() {
+		for e := range stream.C {
+			switch e.Type {
+			case mira.ModMailNewConversation:
+				fmt.Println("New conversation:", e.Conversation.Conversation.Subject)
+			case mira.ModMailNewMessage:
+				fmt.Println("New message by", e.Message.Author.Name, "in", e.Conversation.Conversation.Subject)
+			}
+		}
+		fmt.Println("Stream was closed")
+	}()
+}
+
+// Streams can remember their position across restarts. Without a Checkpointer, a restarted
+// stream sends up to 100 items again & misses everything that happened while it was down.
+func ExampleCheckpointer() {
+	reddit := mira.Init(mira.Credentials{})
+
+	cp, err := mira.NewFileCheckpointer("streams.json")
+	if err != nil {
+		panic(err)
+	}
+	reddit.Config.Checkpointer = cp
+
+	// On the first run, this behaves like every stream. After a restart, it first sends everything
+	// posted since the last item it sent & then continues as usual.
+	stream, err := reddit.Subreddit("pics").StreamPosts()
+	if err != nil {
+		panic(err)
+	}
+	for s := range stream.C {
+		fmt.Println("Received new item in stream:", s.GetID())
+	}
+}
+
+// If you stream many subreddits, a MultiStream polls all of them together & routes each item to its subreddit.
+func ExampleReddit_NewMultiStream() {
+	reddit := mira.Init(mira.Credentials{})
+
+	stream, err := reddit.NewMultiStream(miramodels.KPost)
+	if err != nil {
+		panic(err)
+	}
+
+	// Either receive items of a subreddit on a channel...
+	pics, err := stream.Add("pics")
+	if err != nil {
+		panic(err)
+	}
+	go func() {
+		for s := range pics {
+			fmt.Println("New post in r/pics:", s.GetID())
+		}
+	}()
+
+	// ...or let the stream call a function for each of them.
+	if err := stream.Handle("aww", func(s miramodels.Submission) {
+		fmt.Println("New post in r/aww:", s.GetID())
+	}); err != nil {
+		panic(err)
+	}
+
+	// Subreddits can be removed at any time. This closes the "pics" channel.
+	stream.Remove("pics")
+}
@@ -0,0 +1,253 @@
+package mira
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// firehoseBatch is the number of IDs probed per request, which is the most /api/info accepts.
+const firehoseBatch = 100
+
+// firehoseGapPolls is the number of polls a firehose retries an ID that is missing before giving up on it.
+const firehoseGapPolls = 10
+
+// firehoseMaxGaps is the number of missing IDs a firehose retries at most. Retrying them takes one request per
+// 100 IDs on every poll, so after a large jump (i.e. an outage of /api/info) the oldest ones are given up on.
+const firehoseMaxGaps = 10 * firehoseBatch
+
+// FirehoseComments streams every new comment of the last queued object.
+//
+// Unlike StreamComments, it doesn't poll a listing (which can miss comments if more than 100 arrive
+// between two polls), but uses the fact that IDs are handed out sequentially: It probes the next 100
+// IDs after the newest comment it has seen via /api/info, and filters the results by subreddit
+// locally. Queue the subreddit "all" to receive every comment on reddit.
+//
+// IDs which are skipped by /api/info (i.e. because they are still being replicated) are probed again
+// for the next 10 polls, so late comments are still sent (possibly out of order). IDs still missing after
+// that are given up on, as they most likely belong to private subreddits or have been deleted. At most 1000
+// missing IDs are retried, if there are more, the oldest ones are given up on right away.
+// The checkpoint never moves past a missing ID that hasn't been given up on yet, so after a restart,
+// some comments might be sent a second time.
+//
+// This uses at least one request per poll no matter how quiet the subreddits are, so prefer
+// StreamComments for small subreddits.
+// The fetch interval can be set via reddit.Config.CommentStreamInterval
+// If reddit.Config.Checkpointer is set, the stream resumes where it left off before a restart.
+// Valid objects: Subreddit
+func (c *Reddit) FirehoseComments() (*SubmissionStream, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	return c.streamFirehose(name, models.KComment, "/r/all/comments.json", c.Config.CommentStreamInterval)
+}
+
+// FirehosePosts streams every new post of the last queued object. See FirehoseComments for details.
+// The fetch interval can be set via reddit.Config.PostStreamInterval
+// If reddit.Config.Checkpointer is set, the stream resumes where it left off before a restart.
+// Valid objects: Subreddit
+func (c *Reddit) FirehosePosts() (*SubmissionStream, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	return c.streamFirehose(name, models.KPost, "/r/all/new.json", c.Config.PostStreamInterval)
+}
+
+// firehoseLatest returns the newest ID on reddit according to the latest listing.
+func (c *Reddit) firehoseLatest(latest string) (models.RedditID, error) {
+	list, err := c.miraRequestListing("GET", RedditOauth+latest, map[string]string{
+		"limit": "1",
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(list.Children) < 1 {
+		return "", fmt.Errorf("no results")
+	}
+	return list.Children[0].Data.GetID(), nil
+}
+
+func (c *Reddit) streamFirehose(name string, kind models.RedditKind, latest string, interval int) (*SubmissionStream, error) {
+	filter := make(map[string]bool)
+	if !strings.EqualFold(name, "all") {
+		for _, sr := range strings.Split(name, "+") {
+			filter[strings.ToLower(sr)] = true
+		}
+	}
+
+	key := "firehose:" + string(kind) + ":" + name
+	var hwm models.RedditID
+	if c.Config.Checkpointer != nil {
+		cp, err := c.Config.Checkpointer.Load(key)
+		if err != nil {
+			return nil, err
+		}
+		hwm = models.RedditID(cp)
+	}
+	if hwm == "" {
+		var err error
+		if hwm, err = c.firehoseLatest(latest); err != nil {
+			return nil, err
+		}
+	}
+
+	sendC := make(chan models.Submission, 100)
+	s := &SubmissionStream{
+		C:        sendC,
+		Close:    make(chan struct{}),
+		Interval: newStreamInterval(interval, c.Config.StreamMinInterval, c.Config.StreamMaxInterval),
+	}
+	go func() {
+		atomic.AddInt32(&c.streams, 1)
+		defer atomic.AddInt32(&c.streams, -1)
+		defer close(sendC)
+		// frontier is the newest ID probed so far. All IDs up to high have either been seen or given up on,
+		// missing IDs in between are tracked in gaps along with the number of polls they have been missing for.
+		high := hwm.Number()
+		frontier := high
+		gaps := make(map[uint64]int)
+		send := func(t models.RedditThing) bool {
+			sub, ok := t.(models.Submission)
+			if !ok || (len(filter) > 0 && !filter[strings.ToLower(sub.GetSubreddit())]) {
+				return true
+			}
+			select {
+			case sendC <- sub:
+				return true
+			case <-s.Close:
+				return false
+			}
+		}
+		for {
+			select {
+			case <-s.Close:
+				return
+			default:
+			}
+
+			// IDs can show up late (i.e. while they are still being replicated), so retry the gaps first.
+			missing := make([]uint64, 0, len(gaps))
+			for n := range gaps {
+				missing = append(missing, n)
+			}
+			sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+			for len(missing) > 0 {
+				batch := missing
+				if len(batch) > firehoseBatch {
+					batch = batch[:firehoseBatch]
+				}
+				missing = missing[len(batch):]
+				ids := make([]models.RedditID, len(batch))
+				for i, n := range batch {
+					ids[i] = models.NewRedditID(kind, n)
+				}
+				things, err := c.getInfo(ids)
+				if err != nil {
+					return
+				}
+				sort.Slice(things, func(i, j int) bool { return things[i].GetID().Number() < things[j].GetID().Number() })
+				for _, t := range things {
+					delete(gaps, t.GetID().Number())
+					if !send(t) {
+						return
+					}
+				}
+				for _, n := range batch {
+					if _, ok := gaps[n]; !ok {
+						continue
+					}
+					// Give up on IDs which are gone for good (i.e. in private subreddits).
+					if gaps[n]++; gaps[n] >= firehoseGapPolls {
+						delete(gaps, n)
+					}
+				}
+			}
+
+			found := 0
+			for {
+				ids := make([]models.RedditID, firehoseBatch)
+				for i := range ids {
+					ids[i] = models.NewRedditID(kind, frontier+uint64(i)+1)
+				}
+				things, err := c.getInfo(ids)
+				if err != nil {
+					return
+				}
+				if len(things) == 0 {
+					// Either there is nothing new yet, or all of the next IDs are missing. Move on if
+					// there are newer things & retry the missing ones later.
+					newest, err := c.firehoseLatest(latest)
+					if err != nil {
+						return
+					}
+					if newest.Number() > frontier+firehoseBatch {
+						for i := uint64(1); i <= firehoseBatch; i++ {
+							gaps[frontier+i] = 0
+						}
+						trimGaps(gaps, firehoseMaxGaps)
+						frontier += firehoseBatch
+						continue
+					}
+					break
+				}
+
+				sort.Slice(things, func(i, j int) bool { return things[i].GetID().Number() < things[j].GetID().Number() })
+				returned := make(map[uint64]bool, len(things))
+				for _, t := range things {
+					returned[t.GetID().Number()] = true
+					if !send(t) {
+						return
+					}
+				}
+				newest := things[len(things)-1].GetID().Number()
+				for n := frontier + 1; n < newest; n++ {
+					if !returned[n] {
+						gaps[n] = 0
+					}
+				}
+				trimGaps(gaps, firehoseMaxGaps)
+				frontier = newest
+				found += len(things)
+				// If the last probed ID doesn't exist yet, we have caught up.
+				if things[len(things)-1].GetID() != ids[len(ids)-1] {
+					break
+				}
+			}
+
+			start := high
+			high = frontier
+			for n := range gaps {
+				if n <= high {
+					high = n - 1
+				}
+			}
+			if c.Config.Checkpointer != nil && high != start {
+				if err := c.Config.Checkpointer.Save(key, string(models.NewRedditID(kind, high))); err != nil {
+					return
+				}
+			}
+			c.waitStream(s.Interval.next(found, firehoseBatch), s.Close)
+		}
+	}()
+	return s, nil
+}
+
+// trimGaps gives up on the oldest missing IDs until at most max are left.
+func trimGaps(gaps map[uint64]int, max int) {
+	if len(gaps) <= max {
+		return
+	}
+	missing := make([]uint64, 0, len(gaps))
+	for n := range gaps {
+		missing = append(missing, n)
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+	for _, n := range missing[:len(missing)-max] {
+		delete(gaps, n)
+	}
+}
@@ -0,0 +1,90 @@
+package mira
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFirehoseRetriesGaps(t *testing.T) {
+	var mu sync.Mutex
+	probes := 0
+	ready := make(chan struct{})
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/r/all/comments.json" {
+			fmt.Fprint(w, listingJSON("a"))
+			return
+		}
+		<-ready
+		mu.Lock()
+		probes++
+		// t1_d is still being replicated during the first poll.
+		late := probes == 1
+		mu.Unlock()
+		found := []string{}
+		for _, id := range strings.Split(r.URL.Query().Get("id"), ",") {
+			id = strings.TrimPrefix(id, "t1_")
+			if id >= "b" && id <= "g" && len(id) == 1 && !(late && id == "d") {
+				found = append(found, id)
+			}
+		}
+		fmt.Fprint(w, listingJSON(found...))
+	}))
+	cp := NewMemoryCheckpointer()
+	c.Config.Checkpointer = cp
+
+	s, err := c.Subreddit("test").FirehoseComments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(s.Close)
+	s.Interval.mu.Lock()
+	s.Interval.current, s.Interval.min, s.Interval.max = 10*time.Millisecond, 10*time.Millisecond, 10*time.Millisecond
+	s.Interval.mu.Unlock()
+	close(ready)
+
+	got := []string{}
+	for len(got) < 6 {
+		select {
+		case sub := <-s.C:
+			got = append(got, string(sub.GetID()))
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out, got %v", got)
+		}
+	}
+	want := "t1_b,t1_c,t1_e,t1_f,t1_g,t1_d"
+	if strings.Join(got, ",") != want {
+		t.Errorf("got %v, want %s", got, want)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		saved, _ := cp.Load("firehose:t1:test")
+		if saved == "t1_g" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("checkpoint is %s, want t1_g", saved)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestTrimGaps(t *testing.T) {
+	gaps := map[uint64]int{}
+	for n := uint64(1); n <= 5; n++ {
+		gaps[n] = int(n)
+	}
+	trimGaps(gaps, 10)
+	if len(gaps) != 5 {
+		t.Errorf("trimmed %v below the limit", gaps)
+	}
+	// The oldest IDs are given up on first.
+	trimGaps(gaps, 2)
+	if len(gaps) != 2 || gaps[4] != 4 || gaps[5] != 5 {
+		t.Errorf("got %v, want 4 & 5", gaps)
+	}
+}
@@ -0,0 +1,369 @@
+package mira
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// FlairType selects between link & user flair.
+type FlairType string
+
+// List of all flair types
+const (
+	LinkFlairType FlairType = "LINK_FLAIR"
+	UserFlairType FlairType = "USER_FLAIR"
+)
+
+// FlairSettings are the flair options of a subreddit, see FlairSettings & ConfigureFlair.
+type FlairSettings struct {
+	UserFlairEnabled bool
+	// UserFlairPosition is either "left" or "right".
+	UserFlairPosition string
+	// UserFlairSelfAssign allows users to pick their own flair.
+	UserFlairSelfAssign bool
+	// LinkFlairPosition is "left", "right" or empty to hide link flair.
+	LinkFlairPosition string
+	// LinkFlairSelfAssign allows users to pick the flair of their posts.
+	LinkFlairSelfAssign bool
+}
+
+// FlairTemplates returns the link or user flair templates of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) FlairTemplates(ftype FlairType) ([]*models.FlairTemplate, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	var target string
+	switch ftype {
+	case LinkFlairType:
+		target = RedditOauth + "/r/" + name + "/api/link_flair_v2"
+	case UserFlairType:
+		target = RedditOauth + "/r/" + name + "/api/user_flair_v2"
+	default:
+		return nil, fmt.Errorf("'%s' is not a valid flair type", ftype)
+	}
+	ans, err := c.MiraRequest("GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	ret := []*models.FlairTemplate{}
+	if err := json.Unmarshal(ans, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// AddFlairTemplate creates a new link or user flair template in the last queued object & returns it.
+// Valid objects: Subreddit
+func (c *Reddit) AddFlairTemplate(ftype FlairType, t models.FlairTemplate) (*models.FlairTemplate, error) {
+	t.ID = ""
+	return c.flairTemplateRequest(ftype, t, false)
+}
+
+// UpdateFlairTemplate replaces the flair template with the ID of t in the last queued object & returns it.
+// Valid objects: Subreddit
+func (c *Reddit) UpdateFlairTemplate(ftype FlairType, t models.FlairTemplate) (*models.FlairTemplate, error) {
+	return c.flairTemplateRequest(ftype, t, true)
+}
+
+func (c *Reddit) flairTemplateRequest(ftype FlairType, t models.FlairTemplate, update bool) (*models.FlairTemplate, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	if update && t.ID == "" {
+		return nil, fmt.Errorf("flair template has no ID")
+	}
+	params := map[string]string{
+		"flair_type":       string(ftype),
+		"text":             t.Text,
+		"text_editable":    strconv.FormatBool(t.TextEditable),
+		"background_color": t.BackgroundColor,
+		"text_color":       t.TextColor,
+		"css_class":        t.CSSClass,
+		"mod_only":         strconv.FormatBool(t.ModOnly),
+	}
+	if t.ID != "" {
+		params["flair_template_id"] = t.ID
+	}
+	if t.AllowableContent != "" {
+		params["allowable_content"] = t.AllowableContent
+	}
+	if t.MaxEmojis > 0 {
+		params["max_emojis"] = strconv.Itoa(t.MaxEmojis)
+	}
+	target := RedditOauth + "/r/" + name + "/api/flairtemplate_v2"
+	ans, err := c.MiraRequest("POST", target, params)
+	if err != nil {
+		return nil, err
+	}
+	ret := &models.FlairTemplate{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// DeleteFlairTemplate deletes a link or user flair template of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) DeleteFlairTemplate(id string) error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	target := RedditOauth + "/r/" + name + "/api/deleteflairtemplate"
+	return c.postAPI(target, map[string]string{
+		"flair_template_id": id,
+	})
+}
+
+// SetUserFlair assigns the flair template with the given ID to a user of the last queued object.
+// text overrides the text of the template, leave it empty to use the template text.
+// Valid objects: Subreddit
+func (c *Reddit) SetUserFlair(user, templateID, text string) error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	return c.selectFlair(RedditOauth+"/r/"+name+"/api/selectflair", map[string]string{
+		"name": user,
+	}, templateID, text)
+}
+
+// SelectFlairTemplate assigns the flair template with the given ID to the last queued object.
+// text overrides the text of the template, leave it empty to use the template text.
+// Valid objects: Post
+func (c *Reddit) SelectFlairTemplate(templateID, text string) error {
+	name, _, err := c.checkType(models.KPost)
+	if err != nil {
+		return err
+	}
+	return c.selectFlair(RedditOauth+"/api/selectflair", map[string]string{
+		"link": name,
+	}, templateID, text)
+}
+
+func (c *Reddit) selectFlair(target string, params map[string]string, templateID, text string) error {
+	params["flair_template_id"] = templateID
+	if text != "" {
+		params["text"] = text
+	}
+	return c.postAPI(target, params)
+}
+
+// CurrentFlair returns the flair a user currently has in the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) CurrentFlair(user string) (*models.CurrentFlair, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	target := RedditOauth + "/r/" + name + "/api/flairselector"
+	ans, err := c.MiraRequest("POST", target, map[string]string{
+		"name": user,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &struct {
+		Current *models.CurrentFlair `json:"current"`
+	}{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	if ret.Current == nil {
+		return &models.CurrentFlair{}, nil
+	}
+	return ret.Current, nil
+}
+
+// FlairSettings returns the flair settings of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) FlairSettings() (*FlairSettings, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	return c.getFlairSettings(name)
+}
+
+func (c *Reddit) getFlairSettings(sr string) (*FlairSettings, error) {
+	sub, err := c.getSubreddit(sr)
+	if err != nil {
+		return nil, err
+	}
+	return &FlairSettings{
+		UserFlairEnabled:    sub.UserFlairEnabledInSr,
+		UserFlairPosition:   sub.UserFlairPosition,
+		UserFlairSelfAssign: sub.CanAssignUserFlair,
+		LinkFlairPosition:   sub.LinkFlairPosition,
+		LinkFlairSelfAssign: sub.CanAssignLinkFlair,
+	}, nil
+}
+
+// ConfigureFlair reads the flair settings of the last queued object, lets update change them & writes them back.
+// All settings not touched by update are written back unchanged.
+// Valid objects: Subreddit
+func (c *Reddit) ConfigureFlair(update func(*FlairSettings)) error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	s, err := c.getFlairSettings(name)
+	if err != nil {
+		return err
+	}
+	update(s)
+	target := RedditOauth + "/r/" + name + "/api/flairconfig"
+	return c.postAPI(target, map[string]string{
+		"flair_enabled":                  strconv.FormatBool(s.UserFlairEnabled),
+		"flair_position":                 s.UserFlairPosition,
+		"flair_self_assign_enabled":      strconv.FormatBool(s.UserFlairSelfAssign),
+		"link_flair_position":            s.LinkFlairPosition,
+		"link_flair_self_assign_enabled": strconv.FormatBool(s.LinkFlairSelfAssign),
+	})
+}
+
+// flairCSVRows is the maximum number of rows /api/flaircsv accepts per call.
+const flairCSVRows = 100
+
+// FlairAssignment is a single row for BulkUserFlair. Leave Text & CSSClass empty to remove the flair of User.
+type FlairAssignment struct {
+	User     string
+	Text     string
+	CSSClass string
+}
+
+// FlairResult is the outcome of a single FlairAssignment.
+type FlairResult struct {
+	User   string
+	OK     bool
+	Status string
+	// Err is set if reddit rejected the row.
+	Err error
+}
+
+// BulkUserFlair assigns the flair of many users of the last queued object at once, using one
+// request per 100 users. The results are in the same order as rows.
+// If a request fails, the results of all previous requests are returned together with the error.
+// Valid objects: Subreddit
+func (c *Reddit) BulkUserFlair(rows []FlairAssignment) ([]FlairResult, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	target := RedditOauth + "/r/" + name + "/api/flaircsv"
+
+	ret := make([]FlairResult, 0, len(rows))
+	for start := 0; start < len(rows); start += flairCSVRows {
+		end := start + flairCSVRows
+		if end > len(rows) {
+			end = len(rows)
+		}
+		chunk := rows[start:end]
+
+		buf := new(bytes.Buffer)
+		w := csv.NewWriter(buf)
+		for _, row := range chunk {
+			w.Write([]string{row.User, row.Text, row.CSSClass})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return ret, err
+		}
+
+		ans, err := c.MiraRequest("POST", target, map[string]string{
+			"flair_csv": buf.String(),
+		})
+		if err != nil {
+			return ret, err
+		}
+		results := []struct {
+			OK       bool              `json:"ok"`
+			Status   string            `json:"status"`
+			Errors   map[string]string `json:"errors"`
+			Warnings map[string]string `json:"warnings"`
+		}{}
+		if err := json.Unmarshal(ans, &results); err != nil {
+			return ret, err
+		}
+		for i, row := range chunk {
+			res := FlairResult{User: row.User}
+			if i >= len(results) {
+				res.Err = errors.New("reddit returned no result for this row")
+				ret = append(ret, res)
+				continue
+			}
+			res.OK = results[i].OK
+			res.Status = results[i].Status
+			if !res.OK {
+				msgs := []string{}
+				for field, msg := range results[i].Errors {
+					msgs = append(msgs, field+": "+msg)
+				}
+				sort.Strings(msgs)
+				if len(msgs) == 0 {
+					msgs = append(msgs, res.Status)
+				}
+				res.Err = errors.New(strings.Join(msgs, ", "))
+			}
+			ret = append(ret, res)
+		}
+	}
+	return ret, nil
+}
+
+// FlairPaginator walks through the user flairs of a subreddit. Create one using Reddit.FlairList().
+type FlairPaginator struct {
+	c      *Reddit
+	target string
+	params map[string]string
+	done   bool
+}
+
+// FlairList returns a paginator for all user flairs of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) FlairList() (*FlairPaginator, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	return &FlairPaginator{
+		c:      c,
+		target: RedditOauth + "/r/" + name + "/api/flairlist",
+		params: map[string]string{"limit": "1000"},
+	}, nil
+}
+
+// Done tells you if all flairs have been returned.
+func (p *FlairPaginator) Done() bool { return p.done }
+
+// Next returns the next page of user flairs.
+func (p *FlairPaginator) Next() ([]*models.UserFlair, error) {
+	if p.done {
+		return []*models.UserFlair{}, nil
+	}
+	ans, err := p.c.MiraRequest("GET", p.target, p.params)
+	if err != nil {
+		return nil, err
+	}
+	ret := &struct {
+		Users []*models.UserFlair `json:"users"`
+		Next  string              `json:"next"`
+	}{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	p.params["after"] = ret.Next
+	p.done = ret.Next == ""
+	return ret.Users, nil
+}
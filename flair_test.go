@@ -0,0 +1,122 @@
+package mira
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+func TestConfigureFlairKeepsSettings(t *testing.T) {
+	var posted map[string][]string
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/r/test/about":
+			fmt.Fprint(w, `{"kind":"t5","data":{"display_name":"test","user_flair_enabled_in_sr":true,"user_flair_position":"left","can_assign_user_flair":true,"link_flair_position":"right","can_assign_link_flair":false}}`)
+		case "/r/test/api/flairconfig":
+			if err := r.ParseForm(); err != nil {
+				t.Error(err)
+			}
+			posted = r.PostForm
+			fmt.Fprint(w, `{"json":{"errors":[]}}`)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+
+	err := c.Subreddit("test").ConfigureFlair(func(s *FlairSettings) {
+		s.LinkFlairSelfAssign = true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"flair_enabled":                  "true",
+		"flair_position":                 "left",
+		"flair_self_assign_enabled":      "true",
+		"link_flair_position":            "right",
+		"link_flair_self_assign_enabled": "true",
+	}
+	for k, v := range want {
+		if got := posted[k]; len(got) != 1 || got[0] != v {
+			t.Errorf("%s = %v, want %s", k, got, v)
+		}
+	}
+}
+
+func TestUpdateFlairTemplateWithoutID(t *testing.T) {
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/r/b/api/link_flair_v2" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `[]`)
+	}))
+
+	if _, err := c.Subreddit("a").UpdateFlairTemplate(LinkFlairType, models.FlairTemplate{Text: "x"}); err == nil {
+		t.Error("UpdateFlairTemplate accepted a template without ID")
+	}
+	// The failed call must not leave its subreddit in the queue.
+	if _, err := c.Subreddit("b").FlairTemplates(LinkFlairType); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBulkUserFlair(t *testing.T) {
+	var chunks [][][]string
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/r/test/api/flaircsv" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			return
+		}
+		rows, err := csv.NewReader(strings.NewReader(r.FormValue("flair_csv"))).ReadAll()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		chunks = append(chunks, rows)
+		results := []string{}
+		for _, row := range rows {
+			if row[0] == "bad" {
+				results = append(results, `{"ok":false,"status":"skipped","errors":{"user":"unable to resolve user"}}`)
+				continue
+			}
+			results = append(results, `{"ok":true,"status":"added flair for user `+row[0]+`"}`)
+		}
+		fmt.Fprint(w, "["+strings.Join(results, ",")+"]")
+	}))
+
+	rows := make([]FlairAssignment, 150)
+	for i := range rows {
+		rows[i] = FlairAssignment{User: fmt.Sprintf("user%d", i), Text: "text"}
+	}
+	rows[42] = FlairAssignment{User: "user42", Text: `say "hi", all`, CSSClass: "quoted"}
+	rows[120] = FlairAssignment{User: "bad"}
+
+	results, err := c.Subreddit("test").BulkUserFlair(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 2 || len(chunks[0]) != 100 || len(chunks[1]) != 50 {
+		t.Fatalf("got %d requests, want 100 & 50 rows", len(chunks))
+	}
+	if got := chunks[0][42]; got[0] != "user42" || got[1] != `say "hi", all` || got[2] != "quoted" {
+		t.Errorf("escaped row = %q", got)
+	}
+	if len(results) != len(rows) {
+		t.Fatalf("got %d results, want %d", len(results), len(rows))
+	}
+	for i, res := range results {
+		if res.User != rows[i].User {
+			t.Errorf("result %d is for %s, want %s", i, res.User, rows[i].User)
+		}
+		if wantOK := rows[i].User != "bad"; res.OK != wantOK || (res.Err == nil) != wantOK {
+			t.Errorf("result %d = %+v", i, res)
+		}
+	}
+	if err := results[120].Err; err == nil || err.Error() != "user: unable to resolve user" {
+		t.Errorf("error of rejected row = %v", err)
+	}
+}
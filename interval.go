@@ -0,0 +1,91 @@
+package mira
+
+import (
+	"sync"
+	"time"
+)
+
+// StreamInterval controls how long a stream waits between two polls.
+//
+// The interval adapts to how busy the stream is: If a poll returns a full page, items might have
+// been missed, so the interval is halved down to the minimum. If a poll returns nothing, the
+// interval grows by half up to the maximum. The interval never drops below what the remaining
+// request budget allows for all running streams together.
+//
+// By default, min & max both equal the starting interval, so the stream polls at a fixed interval
+// (only slowed down by the request budget). See reddit.Config on how to opt in to adaptive intervals.
+type StreamInterval struct {
+	mu      sync.Mutex // guards everything below
+	current time.Duration
+	min     time.Duration
+	max     time.Duration
+}
+
+// newStreamInterval creates an interval starting at start seconds. A min or max of 0 is replaced by start.
+func newStreamInterval(start, min, max int) *StreamInterval {
+	if min == 0 {
+		min = start
+	}
+	if max == 0 {
+		max = start
+	}
+	i := &StreamInterval{current: time.Duration(start) * time.Second}
+	i.SetBounds(time.Duration(min)*time.Second, time.Duration(max)*time.Second)
+	return i
+}
+
+// SetBounds sets the shortest & longest time the stream waits between two polls.
+// If min equals max, the stream polls at a fixed interval.
+func (i *StreamInterval) SetBounds(min, max time.Duration) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if max < min {
+		max = min
+	}
+	i.min, i.max = min, max
+	i.current = clampDuration(i.current, min, max)
+}
+
+// Current returns the time the stream will wait after the next poll if nothing changes.
+func (i *StreamInterval) Current() time.Duration {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.current
+}
+
+// next adapts the interval to a poll that returned found new items out of limit possible items.
+func (i *StreamInterval) next(found, limit int) time.Duration {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	switch {
+	case found >= limit:
+		i.current /= 2
+	case found == 0:
+		i.current += i.current / 2
+	}
+	i.current = clampDuration(i.current, i.min, i.max)
+	return i.current
+}
+
+func clampDuration(d, min, max time.Duration) time.Duration {
+	if d < min {
+		return min
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
+
+// waitStream waits d (but at least as long as the request budget requires) or until the stream is closed.
+func (c *Reddit) waitStream(d time.Duration, closeC <-chan struct{}) {
+	if b := c.budgetInterval(); d < b {
+		d = b
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-closeC:
+	case <-t.C:
+	}
+}
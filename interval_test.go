@@ -0,0 +1,46 @@
+package mira
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStreamIntervalNext(t *testing.T) {
+	tests := []struct {
+		name         string
+		current      time.Duration
+		min, max     time.Duration
+		found, limit int
+		want         time.Duration
+	}{
+		{"full page halves", 40 * time.Second, 5 * time.Second, 300 * time.Second, 100, 100, 20 * time.Second},
+		{"nothing new grows by half", 40 * time.Second, 5 * time.Second, 300 * time.Second, 0, 100, 60 * time.Second},
+		{"some new items keep the interval", 40 * time.Second, 5 * time.Second, 300 * time.Second, 10, 100, 40 * time.Second},
+		{"halving stops at min", 8 * time.Second, 5 * time.Second, 300 * time.Second, 100, 100, 5 * time.Second},
+		{"growing stops at max", 250 * time.Second, 5 * time.Second, 300 * time.Second, 0, 100, 300 * time.Second},
+		{"fixed interval", 45 * time.Second, 45 * time.Second, 45 * time.Second, 0, 100, 45 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &StreamInterval{current: tt.current, min: tt.min, max: tt.max}
+			if got := i.next(tt.found, tt.limit); got != tt.want {
+				t.Errorf("next(%d, %d) = %s, want %s", tt.found, tt.limit, got, tt.want)
+			}
+			if got := i.Current(); got != tt.want {
+				t.Errorf("Current() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewStreamIntervalDefaultsToFixed(t *testing.T) {
+	i := newStreamInterval(45, 0, 0)
+	for n := 0; n < 3; n++ {
+		if got := i.next(0, 100); got != 45*time.Second {
+			t.Fatalf("idle poll %d: got %s, want a fixed 45s", n, got)
+		}
+	}
+	if got := newStreamInterval(45, 5, 300).next(0, 100); got <= 45*time.Second {
+		t.Errorf("adaptive interval didn't back off on an idle poll: %s", got)
+	}
+}
@@ -0,0 +1,288 @@
+package mira
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// Media is a file to upload to reddit. Either Path or Reader has to be set.
+type Media struct {
+	// Path is the local file to upload.
+	Path string
+	// Reader is read instead of Path if set.
+	Reader io.Reader
+	// Name is the file name reddit gets to see. Defaults to the base name of Path.
+	Name string
+	// MimeType defaults to a guess based on the extension of Name.
+	MimeType string
+	// Caption & OutboundURL are only used for gallery items.
+	Caption     string
+	OutboundURL string
+}
+
+// uploadLease tells where & how to upload a file.
+type uploadLease struct {
+	Action string `json:"action"`
+	Fields []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"fields"`
+}
+
+// mediaLease is returned by reddit when requesting to upload a file.
+type mediaLease struct {
+	Args  uploadLease `json:"args"`
+	Asset struct {
+		AssetID      string `json:"asset_id"`
+		WebsocketURL string `json:"websocket_url"`
+	} `json:"asset"`
+}
+
+// uploadedMedia is a file which has been uploaded to reddit.
+type uploadedMedia struct {
+	AssetID      string
+	URL          string
+	WebsocketURL string
+}
+
+// open returns the name, mime type & content of m. The returned function has to be called
+// once the content has been read.
+func (m Media) open() (string, string, io.Reader, func(), error) {
+	name := m.Name
+	if name == "" {
+		name = filepath.Base(m.Path)
+	}
+	mimeType := m.MimeType
+	if mimeType == "" {
+		mimeType = mime.TypeByExtension(strings.ToLower(filepath.Ext(name)))
+		if i := strings.Index(mimeType, ";"); i >= 0 {
+			mimeType = mimeType[:i]
+		}
+	}
+	if mimeType == "" {
+		return "", "", nil, nil, fmt.Errorf("can't guess mime type of '%s'", name)
+	}
+	if m.Reader != nil {
+		return name, mimeType, m.Reader, func() {}, nil
+	}
+	if m.Path == "" {
+		return "", "", nil, nil, errors.New("media needs either a path or a reader")
+	}
+	f, err := os.Open(m.Path)
+	if err != nil {
+		return "", "", nil, nil, err
+	}
+	return name, mimeType, f, func() { f.Close() }, nil
+}
+
+// uploadMedia requests an upload lease from reddit & uploads m to the location it points to.
+func (c *Reddit) uploadMedia(m Media) (*uploadedMedia, error) {
+	name, mimeType, r, done, err := m.open()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	ans, err := c.MiraRequest("POST", RedditOauth+"/api/media/asset.json", map[string]string{
+		"filepath": name,
+		"mimetype": mimeType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	lease := &mediaLease{}
+	if err := json.Unmarshal(ans, lease); err != nil {
+		return nil, err
+	}
+	url, err := c.upload(&lease.Args, name, r)
+	if err != nil {
+		return nil, err
+	}
+	return &uploadedMedia{
+		AssetID:      lease.Asset.AssetID,
+		URL:          url,
+		WebsocketURL: lease.Asset.WebsocketURL,
+	}, nil
+}
+
+// upload sends a file to the location given by lease & returns its URL.
+// It uses reddit.Config.UploadClient, or http.DefaultClient if that isn't set.
+func (c *Reddit) upload(lease *uploadLease, name string, r io.Reader) (string, error) {
+	action := lease.Action
+	if strings.HasPrefix(action, "//") {
+		action = "https:" + action
+	}
+
+	// The upload target needs to know the length, so the form can't be streamed.
+	body := new(bytes.Buffer)
+	form := multipart.NewWriter(body)
+	key := ""
+	for _, field := range lease.Fields {
+		if field.Name == "key" {
+			key = field.Value
+		}
+		if err := form.WriteField(field.Name, field.Value); err != nil {
+			return "", err
+		}
+	}
+	part, err := form.CreateFormFile("file", name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return "", err
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	// The upload target is not part of the reddit API, so don't send our token along.
+	req, err := http.NewRequest("POST", action, body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Content-Length", strconv.Itoa(body.Len()))
+	client := c.Config.UploadClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(response.Body)
+	if response.StatusCode >= 300 {
+		return "", fmt.Errorf("uploading '%s' failed with status %s", name, response.Status)
+	}
+
+	// S3 answers with the location of the uploaded file.
+	location := struct {
+		Location string `xml:"Location"`
+	}{}
+	if xml.Unmarshal(buf.Bytes(), &location) == nil && location.Location != "" {
+		return location.Location, nil
+	}
+	return action + "/" + key, nil
+}
+
+// SubmitImage uploads image & submits it as a new post to the last queued object.
+// Reddit processes image posts asynchronously, so the response contains no post ID.
+// Only the flair, NSFW, spoiler, reply & collection settings of opts are used.
+// Valid objects: Subreddit
+func (c *Reddit) SubmitImage(title string, image Media, opts SubmitOptions) (*models.PostActionResponse, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	uploaded, err := c.uploadMedia(image)
+	if err != nil {
+		return nil, err
+	}
+	opts.Kind = kindImage
+	opts.URL = uploaded.URL
+	return c.submit(name, title, opts)
+}
+
+// SubmitVideo uploads video & thumbnail and submits them as a new post to the last queued object.
+// Reddit processes video posts asynchronously, so the response contains no post ID.
+// Only the flair, NSFW, spoiler, reply & collection settings of opts are used.
+// Valid objects: Subreddit
+func (c *Reddit) SubmitVideo(title string, video, thumbnail Media, opts SubmitOptions) (*models.PostActionResponse, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	uploadedVideo, err := c.uploadMedia(video)
+	if err != nil {
+		return nil, err
+	}
+	uploadedThumbnail, err := c.uploadMedia(thumbnail)
+	if err != nil {
+		return nil, err
+	}
+	opts.Kind = kindVideo
+	opts.URL = uploadedVideo.URL
+	opts.posterURL = uploadedThumbnail.URL
+	return c.submit(name, title, opts)
+}
+
+// SubmitGallery uploads all images & submits them as a new gallery post to the last queued object.
+// Each image can have a caption & an outbound URL.
+// Only the flair, NSFW, spoiler, reply & collection settings of opts are used.
+// Valid objects: Subreddit
+func (c *Reddit) SubmitGallery(title string, images []Media, opts SubmitOptions) (*models.PostActionResponse, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	if len(images) < 2 {
+		return nil, errors.New("a gallery needs at least two images")
+	}
+
+	type galleryItem struct {
+		MediaID     string `json:"media_id"`
+		Caption     string `json:"caption"`
+		OutboundURL string `json:"outbound_url"`
+	}
+	items := make([]galleryItem, 0, len(images))
+	for _, image := range images {
+		uploaded, err := c.uploadMedia(image)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, galleryItem{
+			MediaID:     uploaded.AssetID,
+			Caption:     image.Caption,
+			OutboundURL: image.OutboundURL,
+		})
+	}
+
+	payload := map[string]interface{}{
+		"api_type":        "json",
+		"show_error_list": true,
+		"sr":              name,
+		"title":           title,
+		"items":           items,
+		"nsfw":            opts.NSFW,
+		"spoiler":         opts.Spoiler,
+		"sendreplies":     !opts.DisableReplies,
+	}
+	if opts.FlairID != "" {
+		payload["flair_id"] = opts.FlairID
+	}
+	if opts.FlairText != "" {
+		payload["flair_text"] = opts.FlairText
+	}
+	if opts.CollectionID != "" {
+		payload["collection_id"] = opts.CollectionID
+	}
+
+	ans, err := c.miraRequestJSON("POST", RedditOauth+"/api/submit_gallery_post.json", payload)
+	if err != nil {
+		return nil, err
+	}
+	ret := &models.PostActionResponse{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	if len(ret.JSON.Errors) > 0 {
+		return ret, ret.JSON.Errors
+	}
+	return ret, nil
+}
@@ -0,0 +1,76 @@
+package mira
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUploadMedia(t *testing.T) {
+	tests := []struct {
+		name     string
+		location string
+		want     string
+	}{
+		{"location returned", "https://reddit-uploaded-media.s3.amazonaws.com/rte_images/abc", "https://reddit-uploaded-media.s3.amazonaws.com/rte_images/abc"},
+		{"no location returned", "", "https://upload.test/s3/rte_images/abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leased, uploaded := false, false
+			c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Host == "oauth.reddit.com" && r.URL.Path == "/api/media/asset.json":
+					leased = true
+					r.ParseForm()
+					if r.Form.Get("filepath") != "cat.png" || r.Form.Get("mimetype") != "image/png" {
+						t.Errorf("lease requested for %s (%s)", r.Form.Get("filepath"), r.Form.Get("mimetype"))
+					}
+					fmt.Fprint(w, `{"args":{"action":"//upload.test/s3","fields":[{"name":"key","value":"rte_images/abc"},{"name":"policy","value":"secret"}]},"asset":{"asset_id":"abc","websocket_url":"wss://ws.test/abc"}}`)
+				case r.Host == "upload.test" && r.URL.Path == "/s3":
+					uploaded = true
+					if r.Header.Get("Authorization") != "" {
+						t.Error("upload sent the API token")
+					}
+					if err := r.ParseMultipartForm(1 << 20); err != nil {
+						t.Error(err)
+						return
+					}
+					if r.FormValue("key") != "rte_images/abc" || r.FormValue("policy") != "secret" {
+						t.Errorf("missing lease fields: %v", r.MultipartForm.Value)
+					}
+					f, h, err := r.FormFile("file")
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					data, _ := io.ReadAll(f)
+					if h.Filename != "cat.png" || string(data) != "meow" {
+						t.Errorf("got file %s with %q", h.Filename, data)
+					}
+					w.WriteHeader(http.StatusCreated)
+					if tt.location != "" {
+						fmt.Fprintf(w, "<PostResponse><Location>%s</Location></PostResponse>", tt.location)
+					}
+				default:
+					t.Errorf("unexpected request to %s%s", r.Host, r.URL.Path)
+				}
+			}))
+			// Upload requests are not made with the API client, send them to the test server as well.
+			c.Config.UploadClient = &http.Client{Transport: c.Client.Transport}
+
+			got, err := c.uploadMedia(Media{Reader: strings.NewReader("meow"), Name: "cat.png"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !leased || !uploaded {
+				t.Fatalf("leased: %v, uploaded: %v", leased, uploaded)
+			}
+			if got.URL != tt.want || got.AssetID != "abc" || got.WebsocketURL != "wss://ws.test/abc" {
+				t.Errorf("got %+v, want URL %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -15,5 +15,6 @@ func (c *Reddit) SetDefault() {
 	c.Config = redditConfig{
 		CommentStreamInterval: 45,
 		PostStreamInterval:    45,
+		ModMailStreamInterval: 45,
 	}
 }
@@ -0,0 +1,33 @@
+package mira
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// rewriteTransport sends all requests to a local test server instead of reddit.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// newTestReddit returns a Reddit instance which sends all API requests to handler.
+func newTestReddit(t *testing.T, handler http.Handler) *Reddit {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := Init(Credentials{})
+	c.Client = &http.Client{Transport: &rewriteTransport{target: target}}
+	return c
+}
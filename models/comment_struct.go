@@ -67,4 +67,5 @@ type Comment struct {
 	ModReports                 []ModReport         `json:"mod_reports"`
 	SubredditType              string              `json:"subreddit_type"`
 	Ups                        int                 `json:"ups"`
+	Depth                      int                 `json:"depth"`
 }
@@ -0,0 +1,167 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// NewCommentTree creates a CommentTree from the comment listing of a post. Nested replies are parsed as well.
+func NewCommentTree(post *Post, children []RedditElement) (*CommentTree, error) {
+	t := &CommentTree{
+		Post:    post,
+		Replies: []*CommentNode{},
+		More:    []*More{},
+		nodes:   make(map[RedditID]*CommentNode),
+		stubs:   make(map[*More]*CommentNode),
+	}
+	return t, t.Add(children)
+}
+
+// Add inserts comments & More stubs into the tree, e.g. the ones loaded via /api/morechildren.
+// Nested replies are added as well. Comments already in the tree are skipped. Comments whose
+// parent isn't part of the tree (i.e. if only a part of the thread was loaded) are added at the top level.
+func (t *CommentTree) Add(elements []RedditElement) error {
+	for _, e := range elements {
+		switch d := e.Data.(type) {
+		case *Comment:
+			if _, ok := t.nodes[d.Name]; !ok {
+				n := &CommentNode{
+					Comment: d,
+					Tree:    t,
+					Replies: []*CommentNode{},
+					More:    []*More{},
+				}
+				if parent, ok := t.nodes[d.ParentID]; ok {
+					n.Parent = parent
+					parent.Replies = append(parent.Replies, n)
+				} else {
+					t.Replies = append(t.Replies, n)
+				}
+				t.nodes[d.Name] = n
+			}
+			replies, err := d.replies()
+			if err != nil {
+				return err
+			}
+			if err := t.Add(replies); err != nil {
+				return err
+			}
+		case *More:
+			if _, ok := t.stubs[d]; ok {
+				continue
+			}
+			if parent, ok := t.nodes[d.ParentID]; ok {
+				parent.More = append(parent.More, d)
+				t.stubs[d] = parent
+			} else {
+				t.More = append(t.More, d)
+				t.stubs[d] = nil
+			}
+		}
+	}
+	return nil
+}
+
+// replies parses the nested replies of a comment. Reddit returns an empty string if there are none.
+func (c *Comment) replies() ([]RedditElement, error) {
+	if len(c.Replies) == 0 || c.Replies[0] != '{' {
+		return []RedditElement{}, nil
+	}
+	r := &Response{}
+	if err := json.Unmarshal(c.Replies, r); err != nil {
+		return nil, err
+	}
+	list, ok := r.Data.(*Listing)
+	if !ok {
+		return nil, fmt.Errorf("couldn't convert replies to Listing struct. Data has Kind '%s'", r.Kind)
+	}
+	return list.Children, nil
+}
+
+// RemoveMore removes a More stub from the tree, i.e. after its comments have been loaded.
+// The stub is removed from wherever it was added, even if its parent has been added later on.
+func (t *CommentTree) RemoveMore(m *More) {
+	parent, ok := t.stubs[m]
+	if !ok {
+		return
+	}
+	delete(t.stubs, m)
+	if parent != nil {
+		parent.More = removeMore(parent.More, m)
+		return
+	}
+	t.More = removeMore(t.More, m)
+}
+
+func removeMore(list []*More, m *More) []*More {
+	for i, e := range list {
+		if e == m {
+			return append(list[:i], list[i+1:]...)
+		}
+	}
+	return list
+}
+
+// Walk calls f for each comment in the tree, depth first & in tree order.
+// If f returns false, the replies of that comment are skipped.
+func (t *CommentTree) Walk(f func(*CommentNode) bool) {
+	for _, n := range t.Replies {
+		n.Walk(f)
+	}
+}
+
+// Walk calls f for the comment & all of its replies, depth first & in tree order.
+// If f returns false, the replies of that comment are skipped.
+func (n *CommentNode) Walk(f func(*CommentNode) bool) {
+	if !f(n) {
+		return
+	}
+	for _, r := range n.Replies {
+		r.Walk(f)
+	}
+}
+
+// Flatten returns all comments of the tree in tree order.
+func (t *CommentTree) Flatten() []*Comment {
+	ret := []*Comment{}
+	t.Walk(func(n *CommentNode) bool {
+		ret = append(ret, n.Comment)
+		return true
+	})
+	return ret
+}
+
+// Find returns the node of a comment, or nil if the comment isn't part of the tree.
+func (t *CommentTree) Find(id RedditID) *CommentNode {
+	return t.nodes[id]
+}
+
+// Len returns the number of comments in the tree.
+func (t *CommentTree) Len() int { return len(t.nodes) }
+
+// MoreStubs returns all More stubs of the tree, the top level ones first.
+func (t *CommentTree) MoreStubs() []*More {
+	ret := append([]*More{}, t.More...)
+	t.Walk(func(n *CommentNode) bool {
+		ret = append(ret, n.More...)
+		return true
+	})
+	return ret
+}
+
+// Ancestors returns all parents of the comment, starting with the top level comment.
+func (n *CommentNode) Ancestors() []*CommentNode {
+	ret := []*CommentNode{}
+	for p := n.Parent; p != nil; p = p.Parent {
+		ret = append([]*CommentNode{p}, ret...)
+	}
+	return ret
+}
+
+// IsComplete tells you if Ancestors reaches up to the post.
+func (c *CommentContext) IsComplete() bool {
+	if len(c.Ancestors) == 0 {
+		return c.Comment.ParentID == c.Comment.LinkID
+	}
+	return c.Ancestors[0].ParentID == c.Ancestors[0].LinkID
+}
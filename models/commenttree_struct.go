@@ -0,0 +1,31 @@
+package models
+
+// CommentTree is a post together with its comments, nested like they are on reddit.
+// Comments that haven't been loaded yet are represented by More stubs.
+type CommentTree struct {
+	Post    *Post
+	Replies []*CommentNode
+	More    []*More
+
+	nodes map[RedditID]*CommentNode
+	// stubs tells where each More stub has been added: to a comment, or to the top level (nil).
+	stubs map[*More]*CommentNode
+}
+
+// CommentNode is a comment inside a CommentTree. Use Comment.Depth to get its depth.
+type CommentNode struct {
+	*Comment
+	Tree    *CommentTree
+	Parent  *CommentNode // nil for top level comments (and comments whose parent wasn't loaded)
+	Replies []*CommentNode
+	More    []*More
+}
+
+// CommentContext is a comment together with the conversation above it.
+type CommentContext struct {
+	Post *Post
+	// Ancestors are the parents of the comment, starting with the top most one loaded.
+	Ancestors []*Comment
+	// Comment is the comment itself, including its replies.
+	Comment *CommentNode
+}
@@ -0,0 +1,34 @@
+package models
+
+import "testing"
+
+func TestCommentTreeRemoveMoreOutOfOrder(t *testing.T) {
+	post := &Post{Name: "t3_p"}
+	tree, err := NewCommentTree(post, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The stub arrives before the comment it belongs to, so it is added at the top level.
+	stub := &More{Name: "t1_m", ID: "m", ParentID: "t1_a", Children: []string{"b"}}
+	if err := tree.Add([]RedditElement{{Kind: KMore, Data: stub}}); err != nil {
+		t.Fatal(err)
+	}
+	parent := &Comment{Name: "t1_a", ID: "a", ParentID: "t3_p", LinkID: "t3_p"}
+	if err := tree.Add([]RedditElement{{Kind: KComment, Data: parent}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(tree.MoreStubs()); got != 1 {
+		t.Fatalf("got %d stubs, want 1", got)
+	}
+
+	tree.RemoveMore(stub)
+	if got := tree.MoreStubs(); len(got) != 0 {
+		t.Errorf("stub is still part of the tree: %+v", got)
+	}
+	// Adding the same stub twice doesn't duplicate it.
+	tree.Add([]RedditElement{{Kind: KMore, Data: stub}, {Kind: KMore, Data: stub}})
+	if got := len(tree.MoreStubs()); got != 1 {
+		t.Errorf("got %d stubs after adding the same stub twice, want 1", got)
+	}
+}
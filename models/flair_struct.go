@@ -0,0 +1,43 @@
+package models
+
+// FlairTemplate is a link or user flair template of a subreddit.
+type FlairTemplate struct {
+	ID           string `json:"id"`
+	Text         string `json:"text"`
+	TextEditable bool   `json:"text_editable"`
+	// Type is either "text" or "richtext".
+	Type            string `json:"type"`
+	BackgroundColor string `json:"background_color"`
+	// TextColor is either "dark" or "light".
+	TextColor string `json:"text_color"`
+	CSSClass  string `json:"css_class"`
+	ModOnly   bool   `json:"mod_only"`
+	// AllowableContent is one of "all", "emoji" & "text".
+	AllowableContent string          `json:"allowable_content"`
+	MaxEmojis        int             `json:"max_emojis"`
+	Richtext         []FlairRichtext `json:"richtext"`
+}
+
+// FlairRichtext is a part of a richtext flair: Either text (E = "text", T is set)
+// or an emoji (E = "emoji", A & U are set).
+type FlairRichtext struct {
+	E string `json:"e"`
+	T string `json:"t,omitempty"`
+	A string `json:"a,omitempty"`
+	U string `json:"u,omitempty"`
+}
+
+// CurrentFlair is the flair a user or post currently has.
+type CurrentFlair struct {
+	TemplateID string `json:"flair_template_id"`
+	Text       string `json:"flair_text"`
+	CSSClass   string `json:"flair_css_class"`
+	Position   string `json:"flair_position"`
+}
+
+// UserFlair is the flair of a single user, as returned by the flair list of a subreddit.
+type UserFlair struct {
+	User     string `json:"user"`
+	Text     string `json:"flair_text"`
+	CSSClass string `json:"flair_css_class"`
+}
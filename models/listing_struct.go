@@ -38,14 +38,16 @@ func (r *RedditElement) UnmarshalJSON(data []byte) error {
 		r.Data = &Redditor{}
 	case KPost:
 		r.Data = &Post{}
-	//case KMessage:
-	// not implemented
+	case KMessage:
+		r.Data = &Message{}
 	case KSubreddit:
 		r.Data = &Subreddit{}
 	//case KAward:
 	// not implemented
 	case KModAction:
 		r.Data = &ModAction{}
+	case KMore:
+		r.Data = &More{}
 	default:
 		return fmt.Errorf("%q is an invalid RedditKind", m.Kind)
 	}
@@ -54,10 +56,18 @@ func (r *RedditElement) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(m.Data, r.Data)
 }
 
+// APIErrors is the list of errors reddit returns when an action fails. Each error consists of
+// an error code, a message & the name of the offending field.
+//
+// Breaking change: CommentActionResponse.JSON.Errors & PostActionResponse.JSON.Errors used to be
+// a []string, which couldn't hold what reddit returns (decoding silently failed). Code ranging over
+// the old field now gets one []string per error, use APIErrors.Error() to get a readable message.
+type APIErrors [][]string
+
 // CommentActionResponse is returned by reddit when you create a comment (new or reply)
 type CommentActionResponse struct {
 	JSON struct {
-		Errors []string `json:"errors"`
+		Errors APIErrors `json:"errors"`
 		Data   struct {
 			Things []RedditElement `json:"things"`
 		}
@@ -67,10 +77,16 @@ type CommentActionResponse struct {
 // PostActionResponse is returned by reddit when you create a post
 type PostActionResponse struct {
 	JSON struct {
-		Errors []string `json:"errors"`
+		Errors APIErrors `json:"errors"`
 		Data   struct {
 			Name RedditID `json:"name"`
 			URL  string   `json:"url"`
+			// ID is set instead of Name for gallery posts.
+			ID RedditID `json:"id"`
+			// Image & video posts are processed asynchronously, so neither Name nor ID are set.
+			// The post can be found on the users submitted page once reddit is done.
+			UserSubmittedPage string `json:"user_submitted_page"`
+			WebsocketURL      string `json:"websocket_url"`
 		}
 	} `json:"json"`
 }
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// GetID returns the RedditID of the Message
+func (m Message) GetID() RedditID { return m.Name }
+
+// CreatedAt returns the time.Time the Message was sent at
+func (m Message) CreatedAt() time.Time { return time.Unix(int64(m.CreatedUTC), 0) }
+
+// GetURL returns the link to the Message, or to the comment for comment replies & mentions
+func (m Message) GetURL() string {
+	if m.WasComment {
+		return fmt.Sprintf("https://www.reddit.com%s", m.Context)
+	}
+	return fmt.Sprintf("https://www.reddit.com/message/messages/%s", m.ID)
+}
+
+// IsUnread tells you if the Message hasn't been read yet
+func (m Message) IsUnread() bool { return m.New }
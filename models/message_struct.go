@@ -0,0 +1,43 @@
+package models
+
+import "encoding/json"
+
+// Message defines a private message (t4_XXXXX).
+// Listings of the inbox also return comment replies & mentions as Messages, with WasComment set.
+type Message struct {
+	FirstMessage          json.RawMessage `json:"first_message"` // int or null
+	FirstMessageName      RedditID        `json:"first_message_name"`
+	Subreddit             string          `json:"subreddit"`
+	Likes                 bool            `json:"likes"`
+	Replies               json.RawMessage `json:"replies"` // is Response, but empty string if there are none
+	AuthorFullname        RedditID        `json:"author_fullname"`
+	ID                    string          `json:"id"`
+	Subject               string          `json:"subject"`
+	Score                 int             `json:"score"`
+	Author                string          `json:"author"`
+	NumComments           int             `json:"num_comments"`
+	ParentID              RedditID        `json:"parent_id"`
+	SubredditNamePrefixed string          `json:"subreddit_name_prefixed"`
+	New                   bool            `json:"new"`
+	Type                  string          `json:"type"`
+	Body                  string          `json:"body"`
+	Dest                  string          `json:"dest"`
+	WasComment            bool            `json:"was_comment"`
+	BodyHTML              string          `json:"body_html"`
+	Name                  RedditID        `json:"name"`
+	Created               float64         `json:"created"`
+	CreatedUTC            float64         `json:"created_utc"`
+	Context               string          `json:"context"`
+	Distinguished         string          `json:"distinguished"`
+	LinkTitle             string          `json:"link_title"`
+}
+
+// MessageListing is a Listing of Messages, as returned when reading the inbox.
+type MessageListing struct {
+	After    string `json:"after"`
+	Before   string `json:"before"`
+	Children []struct {
+		Kind RedditKind `json:"kind"`
+		Data *Message   `json:"data"`
+	} `json:"children"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// GetID returns the ID of the ModNote - which isn't a RedditID.
+func (n ModNote) GetID() RedditID { return RedditID(n.ID) }
+
+// CreatedAt returns the time the note was created
+func (n ModNote) CreatedAt() time.Time { return time.Unix(int64(n.CreatedUTC), 0) }
+
+// GetURL returns a link to the profile of the user the note is about
+func (n ModNote) GetURL() string {
+	return fmt.Sprintf("https://www.reddit.com/user/%s", n.User)
+}
+
+// IsUserNote tells you if the note was written by a moderator, as opposed to being a logged mod action.
+func (n ModNote) IsUserNote() bool { return n.Type == "NOTE" }
@@ -0,0 +1,44 @@
+package models
+
+// ModNoteLabel categorizes a mod note.
+type ModNoteLabel string
+
+// List of all labels a mod note can have
+const (
+	ModNoteNoLabel          ModNoteLabel = ""
+	ModNoteBotBan           ModNoteLabel = "BOT_BAN"
+	ModNotePermaBan         ModNoteLabel = "PERMA_BAN"
+	ModNoteBan              ModNoteLabel = "BAN"
+	ModNoteAbuseWarning     ModNoteLabel = "ABUSE_WARNING"
+	ModNoteSpamWarning      ModNoteLabel = "SPAM_WARNING"
+	ModNoteSpamWatch        ModNoteLabel = "SPAM_WATCH"
+	ModNoteSolidContributor ModNoteLabel = "SOLID_CONTRIBUTOR"
+	ModNoteHelpfulUser      ModNoteLabel = "HELPFUL_USER"
+)
+
+// ModNote is an entry in the mod notes of a user. It is either a note written by a moderator
+// (Type "NOTE", see UserNoteData) or a mod action logged automatically (see ModActionData).
+type ModNote struct {
+	ID          string  `json:"id"`
+	Type        string  `json:"type"`
+	Subreddit   string  `json:"subreddit"`
+	SubredditID string  `json:"subreddit_id"`
+	User        string  `json:"user"`
+	UserID      string  `json:"user_id"`
+	Operator    string  `json:"operator"`
+	OperatorID  string  `json:"operator_id"`
+	CreatedUTC  float64 `json:"created_at"`
+	Cursor      string  `json:"cursor"`
+
+	UserNoteData struct {
+		Note     string       `json:"note"`
+		Label    ModNoteLabel `json:"label"`
+		RedditID RedditID     `json:"reddit_id"`
+	} `json:"user_note_data"`
+	ModActionData struct {
+		Action      string   `json:"action"`
+		Details     string   `json:"details"`
+		Description string   `json:"description"`
+		RedditID    RedditID `json:"reddit_id"`
+	} `json:"mod_action_data"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import "strings"
+
+// ModPermission is a set of moderator permissions.
+type ModPermission uint
+
+// List of all moderator permissions
+const (
+	PermAll ModPermission = 1 << iota
+	PermAccess
+	PermConfig
+	PermFlair
+	PermMail
+	PermPosts
+	PermWiki
+	PermChatConfig
+	PermChatOperator
+)
+
+// NoPermissions is the empty set, i.e. a moderator without any permissions.
+const NoPermissions ModPermission = 0
+
+var modPermissionNames = []struct {
+	perm ModPermission
+	name string
+}{
+	{PermAll, "all"},
+	{PermAccess, "access"},
+	{PermConfig, "config"},
+	{PermFlair, "flair"},
+	{PermMail, "mail"},
+	{PermPosts, "posts"},
+	{PermWiki, "wiki"},
+	{PermChatConfig, "chat_config"},
+	{PermChatOperator, "chat_operator"},
+}
+
+// ParseModPermissions converts the permission names returned by reddit into a ModPermission.
+// Unknown names are ignored.
+func ParseModPermissions(names []string) ModPermission {
+	var p ModPermission
+	for _, name := range names {
+		for _, n := range modPermissionNames {
+			if strings.EqualFold(n.name, name) {
+				p |= n.perm
+			}
+		}
+	}
+	return p
+}
+
+// Has tells you if all permissions of q are in p. PermAll includes all other permissions.
+func (p ModPermission) Has(q ModPermission) bool {
+	if p&PermAll != 0 {
+		return true
+	}
+	return p&q == q
+}
+
+// Names returns the names of all permissions in p.
+func (p ModPermission) Names() []string {
+	ret := []string{}
+	for _, n := range modPermissionNames {
+		if p&n.perm != 0 {
+			ret = append(ret, n.name)
+		}
+	}
+	return ret
+}
+
+func (p ModPermission) String() string {
+	if p == NoPermissions {
+		return "none"
+	}
+	return strings.Join(p.Names(), ",")
+}
+
+// FormValue returns p in the format reddit expects when setting permissions: Each permission
+// prefixed with + or -.
+func (p ModPermission) FormValue() string {
+	if p&PermAll != 0 {
+		return "+all"
+	}
+	ret := []string{"-all"}
+	for _, n := range modPermissionNames[1:] {
+		if p&n.perm != 0 {
+			ret = append(ret, "+"+n.name)
+		} else {
+			ret = append(ret, "-"+n.name)
+		}
+	}
+	return strings.Join(ret, ",")
+}
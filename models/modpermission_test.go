@@ -0,0 +1,45 @@
+package models_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+func ExampleParseModPermissions() {
+	p := models.ParseModPermissions([]string{"posts", "Mail", "unknown"})
+	fmt.Println(p)
+	fmt.Println(p.Has(models.PermPosts), p.Has(models.PermPosts|models.PermWiki))
+	// Output:
+	// mail,posts
+	// true false
+}
+
+func ExampleModPermission_FormValue() {
+	fmt.Println((models.PermPosts | models.PermMail).FormValue())
+	fmt.Println(models.PermAll.FormValue())
+	fmt.Println(models.NoPermissions.FormValue())
+	// Output:
+	// -all,-access,-config,-flair,+mail,+posts,-wiki,-chat_config,-chat_operator
+	// +all
+	// -all,-access,-config,-flair,-mail,-posts,-wiki,-chat_config,-chat_operator
+}
+
+func TestModPermissionHas(t *testing.T) {
+	tests := []struct {
+		p, q models.ModPermission
+		want bool
+	}{
+		{models.PermAll, models.PermWiki | models.PermFlair, true},
+		{models.PermWiki, models.PermWiki, true},
+		{models.PermWiki, models.PermWiki | models.PermFlair, false},
+		{models.NoPermissions, models.PermMail, false},
+		{models.PermMail, models.NoPermissions, true},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Has(tt.q); got != tt.want {
+			t.Errorf("%s.Has(%s) = %v, want %v", tt.p, tt.q, got, tt.want)
+		}
+	}
+}
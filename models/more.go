@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// GetID returns the RedditID of the More stub
+func (m More) GetID() RedditID { return m.Name }
+
+// CreatedAt returns the zero time, as More stubs aren't created by anyone
+func (m More) CreatedAt() time.Time { return time.Time{} }
+
+// GetURL returns an empty string, as More stubs have no link
+func (m More) GetURL() string { return "" }
+
+// IsContinueThread tells you if the stub is a "continue this thread" link, which can't be loaded
+// via /api/morechildren but needs the subtree of its parent to be fetched.
+func (m More) IsContinueThread() bool { return m.ID == "_" }
@@ -0,0 +1,12 @@
+package models
+
+// More is a stub in a comment tree standing in for comments that haven't been loaded.
+// If ID is "_", it's a "continue this thread" link and Children is empty.
+type More struct {
+	Count    int      `json:"count"`
+	Name     RedditID `json:"name"`
+	ID       string   `json:"id"`
+	ParentID RedditID `json:"parent_id"`
+	Depth    int      `json:"depth"`
+	Children []string `json:"children"`
+}
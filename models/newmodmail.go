@@ -0,0 +1,91 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// UnmarshalJSON converts the messages & mod actions, which reddit sends as maps, into ordered slices.
+// Slices (as written by json.Marshal) are accepted as well.
+func (c *NewModmailConversation) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Conversation NewModmailConversationInfo `json:"conversation"`
+		Messages     json.RawMessage            `json:"messages"`
+		User         NewModmailUser             `json:"user"`
+		ModActions   json.RawMessage            `json:"modActions"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	c.Conversation = raw.Conversation
+	c.User = raw.User
+
+	messages := make(map[string]*NewModmailMessage)
+	if isJSONArray(raw.Messages) {
+		list := []*NewModmailMessage{}
+		if err := json.Unmarshal(raw.Messages, &list); err != nil {
+			return err
+		}
+		for _, m := range list {
+			messages[m.ID] = m
+		}
+	} else if len(raw.Messages) > 0 {
+		if err := json.Unmarshal(raw.Messages, &messages); err != nil {
+			return err
+		}
+	}
+	c.Messages = SortModmailMessages(messages)
+
+	actions := make(map[string]*NewModmailModAction)
+	if isJSONArray(raw.ModActions) {
+		list := []*NewModmailModAction{}
+		if err := json.Unmarshal(raw.ModActions, &list); err != nil {
+			return err
+		}
+		for _, a := range list {
+			actions[a.ID] = a
+		}
+	} else if len(raw.ModActions) > 0 {
+		if err := json.Unmarshal(raw.ModActions, &actions); err != nil {
+			return err
+		}
+	}
+	c.ModActions = make([]*NewModmailModAction, 0, len(actions))
+	for _, a := range actions {
+		c.ModActions = append(c.ModActions, a)
+	}
+	sort.Slice(c.ModActions, func(i, j int) bool {
+		if c.ModActions[i].Date.Equal(c.ModActions[j].Date) {
+			return c.ModActions[i].ID < c.ModActions[j].ID
+		}
+		return c.ModActions[i].Date.Before(c.ModActions[j].Date)
+	})
+	return nil
+}
+
+func isJSONArray(data json.RawMessage) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '['
+}
+
+// SortModmailMessages returns the messages ordered chronologically, oldest first.
+func SortModmailMessages(messages map[string]*NewModmailMessage) []*NewModmailMessage {
+	ret := make([]*NewModmailMessage, 0, len(messages))
+	for _, m := range messages {
+		ret = append(ret, m)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Date.Equal(ret[j].Date) {
+			return ret[i].ID < ret[j].ID
+		}
+		return ret[i].Date.Before(ret[j].Date)
+	})
+	return ret
+}
+
+// GetID returns the ID of the conversation - which isn't a RedditID.
+func (c NewModmailConversation) GetID() RedditID { return RedditID(c.Conversation.ID) }
+
+// IsArchived tells you if the conversation has been archived.
+func (c NewModmailConversation) IsArchived() bool { return c.Conversation.State == 2 }
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewModmailConversationJSON(t *testing.T) {
+	// Reddit sends messages & mod actions as maps keyed by their IDs.
+	data := []byte(`{
+		"conversation": {"id": "abc"},
+		"messages": {
+			"m2": {"id": "m2", "date": "2020-01-02T00:00:00Z", "body": "second"},
+			"m1": {"id": "m1", "date": "2020-01-01T00:00:00Z", "body": "first"}
+		},
+		"modActions": {
+			"a1": {"id": "a1", "date": "2020-01-03T00:00:00Z", "actionTypeId": 0}
+		}
+	}`)
+	conv := &NewModmailConversation{}
+	if err := json.Unmarshal(data, conv); err != nil {
+		t.Fatal(err)
+	}
+	check := func(conv *NewModmailConversation) {
+		t.Helper()
+		if len(conv.Messages) != 2 || conv.Messages[0].ID != "m1" || conv.Messages[1].ID != "m2" {
+			t.Errorf("messages aren't ordered oldest first: %+v", conv.Messages)
+		}
+		if len(conv.ModActions) != 1 || conv.ModActions[0].ID != "a1" {
+			t.Errorf("got mod actions %+v", conv.ModActions)
+		}
+	}
+	check(conv)
+
+	// json.Marshal writes slices, which have to be read back as well.
+	out, err := json.Marshal(conv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again := &NewModmailConversation{}
+	if err := json.Unmarshal(out, again); err != nil {
+		t.Fatal(err)
+	}
+	check(again)
+	if again.Conversation.ID != "abc" {
+		t.Errorf("got conversation %s, want abc", again.Conversation.ID)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -59,11 +60,29 @@ func (r RedditID) Type() RedditKind {
 		return KAward
 	case "modaction":
 		return KModAction
+	case "more":
+		return KMore
 	default:
 		return KUnknown
 	}
 }
 
+// Number returns the base36 part of the RedditID as a number. IDs of the same kind are
+// handed out sequentially, so a higher number means a newer thing. Returns 0 for invalid IDs.
+func (r RedditID) Number() uint64 {
+	s := string(r)
+	n, err := strconv.ParseUint(s[strings.IndexByte(s, '_')+1:], 36, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// NewRedditID creates a RedditID from its kind & number (see RedditID.Number)
+func NewRedditID(kind RedditKind, n uint64) RedditID {
+	return RedditID(string(kind) + "_" + strconv.FormatUint(n, 36))
+}
+
 // RedditKind defines the type of of a Reddit ID (tX)
 type RedditKind string
 
@@ -76,6 +95,8 @@ const (
 	KSubreddit RedditKind = "t5"
 	KAward     RedditKind = "t6"
 	KModAction RedditKind = "modaction"
+	KMore      RedditKind = "more"
+	KModMail   RedditKind = "modmail"
 	KUnknown   RedditKind = "tX"
 )
 
@@ -150,3 +171,17 @@ type Submission interface {
 	GetApproved() SubModAction
 	GetReports() AllReports
 }
+
+func (e APIErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		parts := []string{}
+		for _, p := range err {
+			if p != "" {
+				parts = append(parts, p)
+			}
+		}
+		msgs = append(msgs, strings.Join(parts, ": "))
+	}
+	return strings.Join(msgs, ", ")
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// GetID returns the ID of the Relationship - which isn't a RedditID. Use UserID for the ID of the user.
+func (r Relationship) GetID() RedditID { return RedditID(r.ID) }
+
+// CreatedAt returns the time the relationship was created, i.e. when a user was banned
+func (r Relationship) CreatedAt() time.Time { return time.Unix(int64(r.Date), 0) }
+
+// GetURL returns a link to the profile of the user
+func (r Relationship) GetURL() string {
+	return fmt.Sprintf("https://www.reddit.com/user/%s", r.Name)
+}
+
+// IsPermanent tells you if the relationship doesn't expire.
+func (r Relationship) IsPermanent() bool { return r.DaysLeft == nil }
+
+// Permissions returns the permissions of a moderator.
+func (r Relationship) Permissions() ModPermission { return ParseModPermissions(r.ModPermissions) }
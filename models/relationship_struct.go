@@ -0,0 +1,17 @@
+package models
+
+// Relationship is an entry in one of the user lists of a subreddit (banned, muted, contributors etc).
+type Relationship struct {
+	// ID is the ID of the relationship itself (e.g. rb_XXXXX for bans).
+	ID string `json:"rel_id"`
+	// UserID is the ID of the user.
+	UserID RedditID `json:"id"`
+	Name   string   `json:"name"`
+	Date   float64  `json:"date"`
+	// Note is the mod note of bans & wiki bans.
+	Note string `json:"note"`
+	// DaysLeft is the remaining duration of temporary bans. It is nil for permanent bans & all other relationships.
+	DaysLeft *int `json:"days_left"`
+	// ModPermissions is only set for moderators.
+	ModPermissions []string `json:"mod_permissions"`
+}
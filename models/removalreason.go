@@ -0,0 +1,23 @@
+package models
+
+import "strings"
+
+// ByTitle returns the removal reason with the given title (ignoring case), or nil if there is none.
+func (r RemovalReasons) ByTitle(title string) *RemovalReason {
+	for _, reason := range r {
+		if strings.EqualFold(reason.Title, title) {
+			return reason
+		}
+	}
+	return nil
+}
+
+// ByID returns the removal reason with the given ID, or nil if there is none.
+func (r RemovalReasons) ByID(id string) *RemovalReason {
+	for _, reason := range r {
+		if reason.ID == id {
+			return reason
+		}
+	}
+	return nil
+}
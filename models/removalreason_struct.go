@@ -0,0 +1,11 @@
+package models
+
+// RemovalReason is one of the native removal reasons of a subreddit.
+type RemovalReason struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+// RemovalReasons is the ordered list of removal reasons of a subreddit.
+type RemovalReasons []*RemovalReason
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// CreatedAt returns the time the rule was created
+func (r Rule) CreatedAt() time.Time { return time.Unix(int64(r.CreatedUTC), 0) }
+
+// Number returns rule n, counting from 1 like reddit does ("Rule 3"), or nil if there is none.
+func (r Rules) Number(n int) *Rule {
+	if n < 1 || n > len(r) {
+		return nil
+	}
+	return r[n-1]
+}
+
+// ByName returns the rule with the given short name (ignoring case), or nil if there is none.
+func (r Rules) ByName(shortName string) *Rule {
+	for _, rule := range r {
+		if strings.EqualFold(rule.ShortName, shortName) {
+			return rule
+		}
+	}
+	return nil
+}
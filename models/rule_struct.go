@@ -0,0 +1,16 @@
+package models
+
+// Rule is a rule of a subreddit.
+type Rule struct {
+	// Kind is the type of content the rule applies to: "all", "link" or "comment".
+	Kind            string  `json:"kind"`
+	ShortName       string  `json:"short_name"`
+	Description     string  `json:"description"`
+	DescriptionHTML string  `json:"description_html"`
+	ViolationReason string  `json:"violation_reason"`
+	Priority        int     `json:"priority"`
+	CreatedUTC      float64 `json:"created_utc"`
+}
+
+// Rules are the rules of a subreddit, in the order they are shown.
+type Rules []*Rule
@@ -0,0 +1,33 @@
+package models
+
+import "encoding/json"
+
+// UnmarshalJSON fills the typed fields & keeps all settings in Raw.
+func (s *SubredditSettings) UnmarshalJSON(data []byte) error {
+	type settings SubredditSettings
+	if err := json.Unmarshal(data, (*settings)(s)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &s.Raw)
+}
+
+// Values returns all settings, with the typed fields taking precedence over Raw.
+func (s *SubredditSettings) Values() (map[string]interface{}, error) {
+	type settings SubredditSettings
+	data, err := json.Marshal((*settings)(s))
+	if err != nil {
+		return nil, err
+	}
+	typed := make(map[string]interface{})
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return nil, err
+	}
+	ret := make(map[string]interface{}, len(s.Raw)+len(typed))
+	for k, v := range s.Raw {
+		ret[k] = v
+	}
+	for k, v := range typed {
+		ret[k] = v
+	}
+	return ret, nil
+}
@@ -0,0 +1,41 @@
+package models
+
+// SubredditSettings are the settings of a subreddit as shown to its moderators.
+// Fields without a typed counterpart are kept in Raw, so they can be written back unchanged.
+type SubredditSettings struct {
+	SubredditID           RedditID `json:"subreddit_id"`
+	Title                 string   `json:"title"`
+	PublicDescription     string   `json:"public_description"`
+	Description           string   `json:"description"`
+	SubmitText            string   `json:"submit_text"`
+	SubmitLinkLabel       string   `json:"submit_link_label"`
+	SubmitTextLabel       string   `json:"submit_text_label"`
+	SubredditType         string   `json:"subreddit_type"`
+	ContentOptions        string   `json:"content_options"`
+	Language              string   `json:"language"`
+	Over18                bool     `json:"over_18"`
+	SpoilersEnabled       bool     `json:"spoilers_enabled"`
+	AllowImages           bool     `json:"allow_images"`
+	AllowVideos           bool     `json:"allow_videos"`
+	AllowGalleries        bool     `json:"allow_galleries"`
+	AllowPollPosts        bool     `json:"allow_polls"`
+	ShowMedia             bool     `json:"show_media"`
+	ShowMediaPreview      bool     `json:"show_media_preview"`
+	DefaultSet            bool     `json:"default_set"`
+	AllowDiscovery        bool     `json:"allow_discovery"`
+	ExcludeBannedModqueue bool     `json:"exclude_banned_modqueue"`
+	SuggestedCommentSort  *string  `json:"suggested_comment_sort"`
+	CommentScoreHideMins  int      `json:"comment_score_hide_mins"`
+	WikiEditMode          string   `json:"wikimode"`
+	WikiEditKarma         int      `json:"wiki_edit_karma"`
+	WikiEditAge           int      `json:"wiki_edit_age"`
+	SpamLinks             string   `json:"spam_links"`
+	SpamSelfposts         string   `json:"spam_selfposts"`
+	SpamComments          string   `json:"spam_comments"`
+	Domain                string   `json:"domain"`
+	HeaderHoverText       string   `json:"header_hover_text"`
+	KeyColor              string   `json:"key_color"`
+
+	// Raw contains all settings as returned by reddit, including the ones not listed above.
+	Raw map[string]interface{} `json:"-"`
+}
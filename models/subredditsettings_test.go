@@ -0,0 +1,24 @@
+package models_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+func ExampleSubredditSettings_Values() {
+	s := &models.SubredditSettings{}
+	json.Unmarshal([]byte(`{"title": "Old title", "over_18": false, "new_setting": "kept"}`), s)
+	s.Title = "New title"
+
+	// Changed typed fields win over Raw, settings without a typed field are kept.
+	values, _ := s.Values()
+	for _, k := range []string{"title", "over_18", "new_setting"} {
+		fmt.Printf("%s: %v\n", k, values[k])
+	}
+	// Output:
+	// title: New title
+	// over_18: false
+	// new_setting: kept
+}
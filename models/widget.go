@@ -0,0 +1,84 @@
+package models
+
+import "encoding/json"
+
+// GetID returns the ID of the widget
+func (w WidgetBase) GetID() string { return w.ID }
+
+// GetKind returns the kind of the widget
+func (w WidgetBase) GetKind() WidgetKind { return w.Kind }
+
+// UnmarshalWidget converts a widget returned by reddit into the type matching its kind.
+func UnmarshalWidget(data []byte) (Widget, error) {
+	var base WidgetBase
+	if err := json.Unmarshal(data, &base); err != nil {
+		return nil, err
+	}
+	var w Widget
+	switch base.Kind {
+	case WidgetKindTextArea:
+		w = &TextAreaWidget{}
+	case WidgetKindButton:
+		w = &ButtonWidget{}
+	case WidgetKindCommunityList:
+		w = &CommunityListWidget{}
+	case WidgetKindCalendar:
+		w = &CalendarWidget{}
+	case WidgetKindImage:
+		w = &ImageWidget{}
+	case WidgetKindRules:
+		w = &RulesWidget{}
+	case WidgetKindCustom:
+		w = &CustomWidget{}
+	default:
+		return &UnknownWidget{WidgetBase: base, Raw: data}, nil
+	}
+	if err := json.Unmarshal(data, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// UnmarshalJSON converts all widgets into their types & reads the layout.
+func (w *Widgets) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Items  map[string]json.RawMessage `json:"items"`
+		Layout struct {
+			IDCardWidget    string `json:"idCardWidget"`
+			ModeratorWidget string `json:"moderatorWidget"`
+			Sidebar         struct {
+				Order []string `json:"order"`
+			} `json:"sidebar"`
+			Topbar struct {
+				Order []string `json:"order"`
+			} `json:"topbar"`
+		} `json:"layout"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	w.Items = make(map[string]Widget, len(raw.Items))
+	for id, item := range raw.Items {
+		widget, err := UnmarshalWidget(item)
+		if err != nil {
+			return err
+		}
+		w.Items[id] = widget
+	}
+	w.Sidebar = raw.Layout.Sidebar.Order
+	w.Topbar = raw.Layout.Topbar.Order
+	w.IDCard = raw.Layout.IDCardWidget
+	w.Moderators = raw.Layout.ModeratorWidget
+	return nil
+}
+
+// SidebarWidgets returns the widgets of the sidebar in the order they are shown.
+func (w *Widgets) SidebarWidgets() []Widget {
+	ret := make([]Widget, 0, len(w.Sidebar))
+	for _, id := range w.Sidebar {
+		if widget, ok := w.Items[id]; ok {
+			ret = append(ret, widget)
+		}
+	}
+	return ret
+}
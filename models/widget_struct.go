@@ -0,0 +1,147 @@
+package models
+
+// WidgetKind is the type of a sidebar widget.
+type WidgetKind string
+
+// List of all widget kinds
+const (
+	WidgetKindTextArea      WidgetKind = "textarea"
+	WidgetKindButton        WidgetKind = "button"
+	WidgetKindCommunityList WidgetKind = "community-list"
+	WidgetKindCalendar      WidgetKind = "calendar"
+	WidgetKindImage         WidgetKind = "image"
+	WidgetKindRules         WidgetKind = "subreddit-rules"
+	WidgetKindCustom        WidgetKind = "custom"
+	WidgetKindIDCard        WidgetKind = "id-card"
+	WidgetKindModerators    WidgetKind = "moderators"
+	WidgetKindMenu          WidgetKind = "menu"
+	WidgetKindPostFlair     WidgetKind = "post-flair"
+)
+
+// Widget is a sidebar widget of the new reddit design. Use a type switch to get the details.
+type Widget interface {
+	GetID() string
+	GetKind() WidgetKind
+}
+
+// WidgetBase contains the fields all widgets share.
+type WidgetBase struct {
+	ID        string       `json:"id,omitempty"`
+	Kind      WidgetKind   `json:"kind"`
+	ShortName string       `json:"shortName"`
+	Styles    WidgetStyles `json:"styles"`
+}
+
+// WidgetStyles are the colors of a widget, as hex codes (#RRGGBB). Empty means the default color.
+type WidgetStyles struct {
+	BackgroundColor string `json:"backgroundColor"`
+	HeaderColor     string `json:"headerColor"`
+}
+
+// Widgets are all widgets of a subreddit, together with their layout.
+type Widgets struct {
+	Items map[string]Widget
+	// Sidebar & Topbar contain the IDs of the widgets in the order they are shown.
+	Sidebar []string
+	Topbar  []string
+	// IDCard & Moderators are the IDs of the two widgets every subreddit has.
+	IDCard     string
+	Moderators string
+}
+
+// TextAreaWidget shows markdown text.
+type TextAreaWidget struct {
+	WidgetBase
+	Text     string `json:"text"`
+	TextHTML string `json:"textHtml,omitempty"`
+}
+
+// ButtonWidget shows a list of buttons.
+type ButtonWidget struct {
+	WidgetBase
+	Description     string         `json:"description"`
+	DescriptionHTML string         `json:"descriptionHtml,omitempty"`
+	Buttons         []WidgetButton `json:"buttons"`
+}
+
+// WidgetButton is a single button of a ButtonWidget. Kind is either "text" or "image".
+type WidgetButton struct {
+	Kind      string `json:"kind"`
+	Text      string `json:"text"`
+	URL       string `json:"url"`
+	Color     string `json:"color,omitempty"`
+	TextColor string `json:"textColor,omitempty"`
+	FillColor string `json:"fillColor,omitempty"`
+	// Only used for image buttons.
+	LinkURL string `json:"linkUrl,omitempty"`
+	Height  int    `json:"height,omitempty"`
+	Width   int    `json:"width,omitempty"`
+}
+
+// CommunityListWidget shows a list of subreddits.
+type CommunityListWidget struct {
+	WidgetBase
+	Data []WidgetCommunity `json:"data"`
+}
+
+// WidgetCommunity is a subreddit in a CommunityListWidget. Only Name is used when writing.
+type WidgetCommunity struct {
+	Name        string `json:"name"`
+	Subscribers int    `json:"subscribers,omitempty"`
+	IconURL     string `json:"iconUrl,omitempty"`
+	IsNSFW      bool   `json:"isNSFW,omitempty"`
+}
+
+// CalendarWidget shows the upcoming events of a public google calendar.
+type CalendarWidget struct {
+	WidgetBase
+	GoogleCalendarID string `json:"googleCalendarId"`
+	RequiresSync     bool   `json:"requiresSync"`
+	Configuration    struct {
+		NumEvents       int  `json:"numEvents"`
+		ShowDate        bool `json:"showDate"`
+		ShowDescription bool `json:"showDescription"`
+		ShowLocation    bool `json:"showLocation"`
+		ShowTime        bool `json:"showTime"`
+		ShowTitle       bool `json:"showTitle"`
+	} `json:"configuration"`
+}
+
+// ImageWidget shows one or more images. Images have to be uploaded first, see UploadWidgetImage.
+type ImageWidget struct {
+	WidgetBase
+	Data []WidgetImage `json:"data"`
+}
+
+// WidgetImage is an image of an ImageWidget or CustomWidget.
+type WidgetImage struct {
+	URL     string `json:"url"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	LinkURL string `json:"linkUrl,omitempty"`
+	// Name is only used by CustomWidget, to reference the image in the CSS.
+	Name string `json:"name,omitempty"`
+}
+
+// RulesWidget shows the rules of the subreddit. Display is either "full" or "compact".
+type RulesWidget struct {
+	WidgetBase
+	Display string `json:"display"`
+}
+
+// CustomWidget shows custom HTML, generated from markdown & styled with custom CSS.
+type CustomWidget struct {
+	WidgetBase
+	Text          string        `json:"text"`
+	TextHTML      string        `json:"textHtml,omitempty"`
+	CSS           string        `json:"css"`
+	Height        int           `json:"height"`
+	ImageData     []WidgetImage `json:"imageData"`
+	StylesheetURL string        `json:"stylesheetUrl,omitempty"`
+}
+
+// UnknownWidget is any widget mira has no type for. Raw contains the widget as returned by reddit.
+type UnknownWidget struct {
+	WidgetBase
+	Raw []byte `json:"-"`
+}
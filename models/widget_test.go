@@ -0,0 +1,39 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalWidget(t *testing.T) {
+	data := []byte(`{"id":"widget_1","kind":"community-list","shortName":"Friends","data":[{"name":"pics","subscribers":100}]}`)
+	w, err := UnmarshalWidget(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &CommunityListWidget{
+		WidgetBase: WidgetBase{ID: "widget_1", Kind: WidgetKindCommunityList, ShortName: "Friends"},
+		Data:       []WidgetCommunity{{Name: "pics", Subscribers: 100}},
+	}
+	if !reflect.DeepEqual(w, want) {
+		t.Errorf("got %+v, want %+v", w, want)
+	}
+
+	// Widgets without a type of their own keep the raw data.
+	data = []byte(`{"id":"widget_2","kind":"menu","shortName":"Menu","data":[{"text":"Wiki"}]}`)
+	w, err = UnmarshalWidget(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknown, ok := w.(*UnknownWidget)
+	if !ok {
+		t.Fatalf("got %T, want *UnknownWidget", w)
+	}
+	if unknown.ID != "widget_2" || unknown.Kind != WidgetKindMenu || string(unknown.Raw) != string(data) {
+		t.Errorf("got %+v", unknown)
+	}
+
+	if _, err := UnmarshalWidget([]byte(`[]`)); err == nil {
+		t.Error("got no error for invalid JSON")
+	}
+}
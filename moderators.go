@@ -0,0 +1,116 @@
+package mira
+
+import (
+	"github.com/ttgmpsn/mira/models"
+)
+
+// Moderators returns all moderators of the last queued object, in the order they are listed.
+// Use Permissions() on each of them to get their permissions.
+// Valid objects: Subreddit
+func (c *Reddit) Moderators() ([]*models.Relationship, error) {
+	p, err := c.relationships(RelModerator)
+	if err != nil {
+		return nil, err
+	}
+	ret := []*models.Relationship{}
+	for !p.Done() {
+		mods, err := p.Next()
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, mods...)
+	}
+	return ret, nil
+}
+
+// MyPermissions returns the moderator permissions of the logged in user in the last queued object.
+// It returns models.NoPermissions if the user isn't a moderator.
+// Valid objects: Subreddit
+func (c *Reddit) MyPermissions() (models.ModPermission, error) {
+	p, err := c.relationships(RelModerator)
+	if err != nil {
+		return models.NoPermissions, err
+	}
+	me, err := c.getMe()
+	if err != nil {
+		return models.NoPermissions, err
+	}
+	mod, err := p.Find(me.Name)
+	if err != nil || mod == nil {
+		return models.NoPermissions, err
+	}
+	return mod.Permissions(), nil
+}
+
+// InviteModerator invites redditor to become a moderator of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) InviteModerator(redditor string, perms models.ModPermission) error {
+	return c.relationshipAction(true, RelModeratorInvite, redditor, map[string]string{
+		"permissions": perms.FormValue(),
+	})
+}
+
+// RevokeInvite revokes the moderator invite of redditor to the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) RevokeInvite(redditor string) error {
+	return c.relationshipAction(false, RelModeratorInvite, redditor, nil)
+}
+
+// RemoveModerator removes redditor from the moderators of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) RemoveModerator(redditor string) error {
+	return c.relationshipAction(false, RelModerator, redditor, nil)
+}
+
+// SetModeratorPermissions changes the permissions of a moderator of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) SetModeratorPermissions(redditor string, perms models.ModPermission) error {
+	return c.setPermissions(RelModerator, redditor, perms)
+}
+
+// SetInvitePermissions changes the permissions of a pending moderator invite to the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) SetInvitePermissions(redditor string, perms models.ModPermission) error {
+	return c.setPermissions(RelModeratorInvite, redditor, perms)
+}
+
+func (c *Reddit) setPermissions(rtype RelationshipType, redditor string, perms models.ModPermission) error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	target := RedditOauth + "/r/" + name + "/api/setpermissions"
+	return c.postAPI(target, map[string]string{
+		"name":        redditor,
+		"type":        string(rtype),
+		"permissions": perms.FormValue(),
+	})
+}
+
+// AcceptModInvite accepts the pending moderator invite of the logged in user to the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) AcceptModInvite() error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	target := RedditOauth + "/r/" + name + "/api/accept_moderator_invite"
+	return c.postAPI(target, map[string]string{})
+}
+
+// LeaveModerator removes the logged in user from the moderators of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) LeaveModerator() error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	sr, err := c.getSubreddit(name)
+	if err != nil {
+		return err
+	}
+	target := RedditOauth + "/api/leavemoderator"
+	return c.postAPI(target, map[string]string{
+		"id": string(sr.Name),
+	})
+}
@@ -0,0 +1,30 @@
+package mira
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestLeaveModerator(t *testing.T) {
+	var left string
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/r/test/about":
+			fmt.Fprint(w, `{"kind":"t5","data":{"display_name":"test","name":"t5_abc"}}`)
+		case "/api/leavemoderator":
+			left = r.FormValue("id")
+			fmt.Fprint(w, `{"json":{"errors":[["NOT_MODERATOR","you are not a moderator","id"]]}}`)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+
+	err := c.Subreddit("test").LeaveModerator()
+	if left != "t5_abc" {
+		t.Errorf("left %q, want t5_abc", left)
+	}
+	if err == nil || err.Error() != "NOT_MODERATOR: you are not a moderator: id" {
+		t.Errorf("got %v, want reddit's error", err)
+	}
+}
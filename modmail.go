@@ -0,0 +1,235 @@
+package mira
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// ModMailSort defines the order of modmail conversations.
+type ModMailSort string
+
+// List of all modmail sort orders
+const (
+	ModMailSortRecent ModMailSort = "recent"
+	ModMailSortMod    ModMailSort = "mod"
+	ModMailSortUser   ModMailSort = "user"
+	ModMailSortUnread ModMailSort = "unread"
+)
+
+// ModMailPaginator walks through modmail conversations. Create one using Reddit.ListConversations().
+type ModMailPaginator struct {
+	c      *Reddit
+	params map[string]string
+	done   bool
+}
+
+// ListConversations returns a paginator for the modmail conversations of the last queued object.
+// If Me is queued, the conversations of all subreddits you moderate are returned.
+// Each returned conversation only contains its newest message, use GetModMailByID to get all of them.
+// Valid objects: Subreddit, Me
+func (c *Reddit) ListConversations(sort ModMailSort, state ModMailState) (*ModMailPaginator, error) {
+	name, ttype := c.getQueue()
+	entity := ""
+	switch ttype {
+	case models.KSubreddit:
+		entity = strings.ReplaceAll(name, "+", ",")
+	case "me":
+	default:
+		return nil, fmt.Errorf("'%s' type does not have an option for listconversations", ttype)
+	}
+	return &ModMailPaginator{
+		c: c,
+		params: map[string]string{
+			"entity": entity,
+			"sort":   string(sort),
+			"state":  string(state),
+			"limit":  "100",
+		},
+	}, nil
+}
+
+// Done tells you if all conversations have been returned.
+func (p *ModMailPaginator) Done() bool { return p.done }
+
+// Next returns the next page of conversations.
+func (p *ModMailPaginator) Next() ([]*models.NewModmailConversation, error) {
+	if p.done {
+		return []*models.NewModmailConversation{}, nil
+	}
+	ans, err := p.c.MiraRequest("GET", RedditOauth+"/api/mod/conversations", p.params)
+	if err != nil {
+		return nil, err
+	}
+	list := &struct {
+		Conversations   map[string]models.NewModmailConversationInfo `json:"conversations"`
+		Messages        map[string]*models.NewModmailMessage         `json:"messages"`
+		ConversationIDs []string                                     `json:"conversationIds"`
+	}{}
+	if err := json.Unmarshal(ans, list); err != nil {
+		return nil, err
+	}
+
+	ret := make([]*models.NewModmailConversation, 0, len(list.ConversationIDs))
+	for _, id := range list.ConversationIDs {
+		conv := &models.NewModmailConversation{Conversation: list.Conversations[id]}
+		messages := make(map[string]*models.NewModmailMessage)
+		for _, obj := range conv.Conversation.ObjIds {
+			if m, ok := list.Messages[obj.ID]; ok && obj.Key == "messages" {
+				messages[obj.ID] = m
+			}
+		}
+		conv.Messages = models.SortModmailMessages(messages)
+		ret = append(ret, conv)
+	}
+	if len(list.ConversationIDs) > 0 {
+		p.params["after"] = list.ConversationIDs[len(list.ConversationIDs)-1]
+	}
+	p.done = len(list.ConversationIDs) < 100
+	return ret, nil
+}
+
+// ModMailReply sends a message to the last queued object & returns the updated conversation.
+// Internal messages are only visible to moderators. If isAuthorHidden is set, the message is sent
+// as the subreddit instead of your user.
+// Valid objects: ModMail
+func (c *Reddit) ModMailReply(body string, isInternal, isAuthorHidden bool) (*models.NewModmailConversation, error) {
+	id, _, err := c.checkType(models.KModMail)
+	if err != nil {
+		return nil, err
+	}
+	return c.modMailConversationRequest("POST", "/api/mod/conversations/"+id, map[string]string{
+		"body":           body,
+		"isInternal":     strconv.FormatBool(isInternal),
+		"isAuthorHidden": strconv.FormatBool(isAuthorHidden),
+	})
+}
+
+// CreateConversation starts a new modmail conversation from the last queued object with
+// the given user & returns it.
+// Valid objects: Subreddit
+func (c *Reddit) CreateConversation(to, subject, body string, isAuthorHidden bool) (*models.NewModmailConversation, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	return c.modMailConversationRequest("POST", "/api/mod/conversations", map[string]string{
+		"srName":         name,
+		"to":             to,
+		"subject":        subject,
+		"body":           body,
+		"isAuthorHidden": strconv.FormatBool(isAuthorHidden),
+	})
+}
+
+// Archive the last queued object.
+// Valid objects: ModMail
+func (c *Reddit) Archive() error {
+	return c.modMailAction("POST", "archive", nil)
+}
+
+// Unarchive the last queued object.
+// Valid objects: ModMail
+func (c *Reddit) Unarchive() error {
+	return c.modMailAction("POST", "unarchive", nil)
+}
+
+// Highlight the last queued object.
+// Valid objects: ModMail
+func (c *Reddit) Highlight() error {
+	return c.modMailAction("POST", "highlight", nil)
+}
+
+// Unhighlight the last queued object.
+// Valid objects: ModMail
+func (c *Reddit) Unhighlight() error {
+	return c.modMailAction("DELETE", "highlight", nil)
+}
+
+// MuteUser mutes the user of the last queued object in modmail. Reddit only allows 72, 168 & 672 hours.
+// Valid objects: ModMail
+func (c *Reddit) MuteUser(hours int) error {
+	return c.modMailAction("POST", "mute", map[string]string{
+		"num_hours": strconv.Itoa(hours),
+	})
+}
+
+// UnmuteUser unmutes the user of the last queued object in modmail.
+// Valid objects: ModMail
+func (c *Reddit) UnmuteUser() error {
+	return c.modMailAction("POST", "unmute", nil)
+}
+
+// TempBan bans the user of the last queued object from the subreddit for days days.
+// Valid objects: ModMail
+func (c *Reddit) TempBan(days int) error {
+	return c.modMailAction("POST", "temp_ban", map[string]string{
+		"duration": strconv.Itoa(days),
+	})
+}
+
+// UnbanUser unbans the user of the last queued object from the subreddit.
+// Valid objects: ModMail
+func (c *Reddit) UnbanUser() error {
+	return c.modMailAction("POST", "unban", nil)
+}
+
+// ApproveUser makes the user of the last queued object an approved user of the subreddit.
+// Valid objects: ModMail
+func (c *Reddit) ApproveUser() error {
+	return c.modMailAction("POST", "approve", nil)
+}
+
+// MarkModMailRead marks multiple modmail conversations as read, without them needing to be queued up.
+func (c *Reddit) MarkModMailRead(ids ...string) error {
+	_, err := c.MiraRequest("POST", RedditOauth+"/api/mod/conversations/read", map[string]string{
+		"conversationIds": strings.Join(ids, ","),
+	})
+	return err
+}
+
+// MarkModMailUnread marks multiple modmail conversations as unread, without them needing to be queued up.
+func (c *Reddit) MarkModMailUnread(ids ...string) error {
+	_, err := c.MiraRequest("POST", RedditOauth+"/api/mod/conversations/unread", map[string]string{
+		"conversationIds": strings.Join(ids, ","),
+	})
+	return err
+}
+
+// ModMailUnreadCount returns the number of unread modmail conversations of all subreddits you moderate, by state.
+func (c *Reddit) ModMailUnreadCount() (map[ModMailState]int, error) {
+	ans, err := c.MiraRequest("GET", RedditOauth+"/api/mod/conversations/unread/count", nil)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[ModMailState]int)
+	if err := json.Unmarshal(ans, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// modMailAction calls a conversation endpoint of the last queued object.
+func (c *Reddit) modMailAction(method, action string, params map[string]string) error {
+	id, _, err := c.checkType(models.KModMail)
+	if err != nil {
+		return err
+	}
+	_, err = c.MiraRequest(method, RedditOauth+"/api/mod/conversations/"+id+"/"+action, params)
+	return err
+}
+
+func (c *Reddit) modMailConversationRequest(method, path string, params map[string]string) (*models.NewModmailConversation, error) {
+	ans, err := c.MiraRequest(method, RedditOauth+path, params)
+	if err != nil {
+		return nil, err
+	}
+	ret := &models.NewModmailConversation{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
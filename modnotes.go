@@ -0,0 +1,211 @@
+package mira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// ModNoteFilter limits the types of mod notes returned by ModNotes.
+type ModNoteFilter string
+
+// List of all mod note filters
+const (
+	ModNotesAll           ModNoteFilter = ""
+	ModNotesNote          ModNoteFilter = "NOTE"
+	ModNotesApproval      ModNoteFilter = "APPROVAL"
+	ModNotesRemoval       ModNoteFilter = "REMOVAL"
+	ModNotesBan           ModNoteFilter = "BAN"
+	ModNotesMute          ModNoteFilter = "MUTE"
+	ModNotesInvite        ModNoteFilter = "INVITE"
+	ModNotesSpam          ModNoteFilter = "SPAM"
+	ModNotesContentChange ModNoteFilter = "CONTENT_CHANGE"
+	ModNotesModAction     ModNoteFilter = "MOD_ACTION"
+)
+
+// ModNotePaginator walks through the mod notes of a user, newest first. Create one using Reddit.ModNotes().
+type ModNotePaginator struct {
+	c      *Reddit
+	params map[string]string
+	done   bool
+}
+
+// ModNotes returns a paginator for the mod notes of user in the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) ModNotes(user string, filter ModNoteFilter) (*ModNotePaginator, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	p := &ModNotePaginator{
+		c: c,
+		params: map[string]string{
+			"subreddit": name,
+			"user":      user,
+			"limit":     "100",
+		},
+	}
+	if filter != ModNotesAll {
+		p.params["filter"] = string(filter)
+	}
+	return p, nil
+}
+
+// Done tells you if all notes have been returned.
+func (p *ModNotePaginator) Done() bool { return p.done }
+
+// Next returns the next page of mod notes.
+func (p *ModNotePaginator) Next() ([]*models.ModNote, error) {
+	if p.done {
+		return []*models.ModNote{}, nil
+	}
+	ans, err := p.c.MiraRequest("GET", RedditOauth+"/api/mod/notes", p.params)
+	if err != nil {
+		return nil, err
+	}
+	ret := &struct {
+		ModNotes    []*models.ModNote `json:"mod_notes"`
+		EndCursor   string            `json:"end_cursor"`
+		HasNextPage bool              `json:"has_next_page"`
+	}{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	p.params["before"] = ret.EndCursor
+	p.done = !ret.HasNextPage || ret.EndCursor == ""
+	return ret.ModNotes, nil
+}
+
+// RecentModNotes returns the newest mod note of each user in the last queued object.
+// The result has the same order as users. Users without notes get a nil entry.
+// Valid objects: Subreddit
+func (c *Reddit) RecentModNotes(users ...string) ([]*models.ModNote, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return []*models.ModNote{}, nil
+	}
+	subreddits := make([]string, len(users))
+	for i := range users {
+		subreddits[i] = name
+	}
+	ans, err := c.MiraRequest("GET", RedditOauth+"/api/mod/notes/recent", map[string]string{
+		"subreddits": strings.Join(subreddits, ","),
+		"users":      strings.Join(users, ","),
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &struct {
+		ModNotes []*models.ModNote `json:"mod_notes"`
+	}{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	return ret.ModNotes, nil
+}
+
+// AddModNote adds a note about user to the last queued object. label can be empty (models.ModNoteNoLabel).
+// item optionally links the note to a post or comment, it can be empty.
+// Valid objects: Subreddit
+func (c *Reddit) AddModNote(user, note string, label models.ModNoteLabel, item models.RedditID) (*models.ModNote, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	return c.addModNote(name, user, note, label, item)
+}
+
+func (c *Reddit) addModNote(sr, user, note string, label models.ModNoteLabel, item models.RedditID) (*models.ModNote, error) {
+	if err := checkModNote(note, label); err != nil {
+		return nil, err
+	}
+	params := map[string]string{
+		"subreddit": sr,
+		"user":      user,
+		"note":      note,
+	}
+	if label != models.ModNoteNoLabel {
+		params["label"] = string(label)
+	}
+	if item != "" {
+		params["reddit_id"] = string(item)
+	}
+	ans, err := c.MiraRequest("POST", RedditOauth+"/api/mod/notes", params)
+	if err != nil {
+		return nil, err
+	}
+	ret := &struct {
+		Created *models.ModNote `json:"created"`
+	}{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	return ret.Created, nil
+}
+
+// checkModNote returns an error if reddit would reject the note.
+func checkModNote(note string, label models.ModNoteLabel) error {
+	if note == "" {
+		return errors.New("mod notes can't be empty")
+	}
+	switch label {
+	case models.ModNoteNoLabel, models.ModNoteBotBan, models.ModNotePermaBan, models.ModNoteBan,
+		models.ModNoteAbuseWarning, models.ModNoteSpamWarning, models.ModNoteSpamWatch,
+		models.ModNoteSolidContributor, models.ModNoteHelpfulUser:
+		return nil
+	default:
+		return fmt.Errorf("'%s' is not a valid mod note label", label)
+	}
+}
+
+// DeleteModNote deletes a note about user from the last queued object.
+// Only notes written by moderators can be deleted.
+// Valid objects: Subreddit
+func (c *Reddit) DeleteModNote(user, noteID string) error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	// This endpoint expects the parameters in the URL.
+	query := url.Values{}
+	query.Set("subreddit", name)
+	query.Set("user", user)
+	query.Set("note_id", noteID)
+	_, err = c.MiraRequest("DELETE", RedditOauth+"/api/mod/notes?"+query.Encode(), nil)
+	return err
+}
+
+// BanWithNote bans a redditor from the last queued object (see Ban) & adds a mod note about it.
+// The note is linked to context, which can be empty.
+// Valid objects: Subreddit
+func (c *Reddit) BanWithNote(redditor string, days int, context, message, reason, note string, label models.ModNoteLabel) error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	if label == models.ModNoteNoLabel {
+		label = models.ModNoteBan
+		if days == 0 {
+			label = models.ModNotePermaBan
+		}
+	}
+	// Check the note first, so the user doesn't end up banned without it.
+	if err := checkModNote(note, label); err != nil {
+		return err
+	}
+	if err := c.ban(name, redditor, days, context, message, reason); err != nil {
+		return err
+	}
+	_, err = c.addModNote(name, redditor, note, label, models.RedditID(context))
+	if err != nil {
+		return fmt.Errorf("user was banned, but adding the mod note failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package mira
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+func TestAddModNote(t *testing.T) {
+	tests := []struct {
+		name  string
+		note  string
+		label models.ModNoteLabel
+		item  models.RedditID
+		want  url.Values // nil if the note is rejected without a request
+	}{
+		{
+			name: "plain",
+			note: "watch out",
+			want: url.Values{"subreddit": {"test"}, "user": {"bob"}, "note": {"watch out"}},
+		},
+		{
+			name:  "label & item",
+			note:  "spammy",
+			label: models.ModNoteSpamWatch,
+			item:  "t3_p",
+			want:  url.Values{"subreddit": {"test"}, "user": {"bob"}, "note": {"spammy"}, "label": {"SPAM_WATCH"}, "reddit_id": {"t3_p"}},
+		},
+		{name: "empty note", note: ""},
+		{name: "invalid label", note: "x", label: "FRIEND"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got url.Values
+			c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "POST" || r.URL.Path != "/api/mod/notes" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				if err := r.ParseForm(); err != nil {
+					t.Error(err)
+				}
+				got = r.PostForm
+				fmt.Fprint(w, `{"created":{"id":"ModNote_1","type":"NOTE","user_note_data":{"note":"x"}}}`)
+			}))
+
+			note, err := c.Subreddit("test").AddModNote("bob", tt.note, tt.label, tt.item)
+			if tt.want == nil {
+				if err == nil || got != nil {
+					t.Errorf("got error %v after sending %v, want an error without a request", err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if note.ID != "ModNote_1" {
+				t.Errorf("got note %+v", note)
+			}
+			if got.Encode() != tt.want.Encode() {
+				t.Errorf("sent %s, want %s", got.Encode(), tt.want.Encode())
+			}
+		})
+	}
+}
+
+func TestModNotesPagination(t *testing.T) {
+	var queries []string
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Encode())
+		if r.URL.Query().Get("before") == "" {
+			fmt.Fprint(w, `{"mod_notes":[{"id":"n2"},{"id":"n1"}],"end_cursor":"c1","has_next_page":true}`)
+			return
+		}
+		fmt.Fprint(w, `{"mod_notes":[{"id":"n0"}],"end_cursor":"c0","has_next_page":false}`)
+	}))
+
+	p, err := c.Subreddit("test").ModNotes("bob", ModNotesBan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{}
+	for !p.Done() {
+		notes, err := p.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, n := range notes {
+			ids = append(ids, n.ID)
+		}
+	}
+	if fmt.Sprint(ids) != "[n2 n1 n0]" {
+		t.Errorf("got notes %v", ids)
+	}
+	want := []string{
+		"filter=BAN&limit=100&subreddit=test&user=bob",
+		"before=c1&filter=BAN&limit=100&subreddit=test&user=bob",
+	}
+	if fmt.Sprint(queries) != fmt.Sprint(want) {
+		t.Errorf("got queries %q, want %q", queries, want)
+	}
+	if notes, err := p.Next(); err != nil || len(notes) != 0 || len(queries) != 2 {
+		t.Errorf("Next after the last page returned %v, %v", notes, err)
+	}
+}
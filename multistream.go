@@ -0,0 +1,373 @@
+package mira
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// maxGroupLength is the longest combined subreddit name ("a+b+c") a MultiStream fetches at once.
+// This leaves enough room for the rest of the URL to stay within common URL length limits.
+const maxGroupLength = 1900
+
+// MultiStream polls posts or comments of many subreddits with a single poller and routes
+// each item to the channels & handlers registered for its subreddit.
+//
+// Subreddits are fetched as combined listings ("a+b+c"). If the combined name gets too long,
+// the subreddits are split into several groups which are fetched one after another.
+// Subreddits can be added & removed while the stream is running.
+//
+// If a group can't be fetched (e.g. because one of its subreddits went private or got banned), each of
+// its subreddits is checked on its own. Failing subreddits are fetched separately from then on, so they
+// don't hold up the others, and are removed after failing 3 polls in a row.
+// Each failure is sent to Errors as a *MultiStreamError.
+//
+// Close the Close channel to stop the stream. This also closes all channels returned by Add & Errors.
+type MultiStream struct {
+	Close    chan struct{}
+	Interval *StreamInterval
+	// Errors receives failures of single subreddits. Errors are dropped if the channel is full.
+	Errors <-chan error
+
+	c      *Reddit
+	target string
+	params map[string]string
+	errC   chan error
+
+	mu       sync.Mutex // guards routes, removed & failures
+	routes   map[string]*multiRoute
+	removed  []*multiRoute
+	failures map[string]int
+}
+
+// multiMaxFailures is the number of polls in a row a subreddit of a MultiStream may fail before it is removed.
+const multiMaxFailures = 3
+
+// MultiStreamError is sent to MultiStream.Errors when a subreddit can't be fetched.
+type MultiStreamError struct {
+	Subreddit string
+	// Removed tells if the subreddit has been removed from the stream because it failed too often.
+	Removed bool
+	Err     error
+}
+
+func (e *MultiStreamError) Error() string {
+	if e.Removed {
+		return fmt.Sprintf("streaming r/%s failed, removed it from the stream: %s", e.Subreddit, e.Err)
+	}
+	return fmt.Sprintf("streaming r/%s failed: %s", e.Subreddit, e.Err)
+}
+
+func (e *MultiStreamError) Unwrap() error { return e.Err }
+
+type multiRoute struct {
+	name     string
+	channels []chan models.Submission
+	handlers []func(models.Submission)
+}
+
+// NewMultiStream creates an empty MultiStream for either posts (models.KPost) or comments (models.KComment).
+// Use Add or Handle to add subreddits.
+// The interval starts at reddit.Config.PostStreamInterval or reddit.Config.CommentStreamInterval.
+func (c *Reddit) NewMultiStream(kind models.RedditKind) (*MultiStream, error) {
+	errC := make(chan error, 100)
+	m := &MultiStream{
+		Close:    make(chan struct{}),
+		Errors:   errC,
+		c:        c,
+		errC:     errC,
+		routes:   make(map[string]*multiRoute),
+		failures: make(map[string]int),
+	}
+	switch kind {
+	case models.KPost:
+		m.target = "/new.json"
+		m.params = map[string]string{}
+		m.Interval = newStreamInterval(c.Config.PostStreamInterval, c.Config.StreamMinInterval, c.Config.StreamMaxInterval)
+	case models.KComment:
+		m.target = "/comments.json"
+		m.params = map[string]string{"sort": "new"}
+		m.Interval = newStreamInterval(c.Config.CommentStreamInterval, c.Config.StreamMinInterval, c.Config.StreamMaxInterval)
+	default:
+		return nil, fmt.Errorf("'%s' type can not be streamed", kind)
+	}
+	go m.run()
+	return m, nil
+}
+
+// Add starts streaming sr (if it isn't already) and returns a channel receiving all of its items.
+// Keep reading from the channel: once it is full, the stream waits for it before routing any other item.
+func (m *MultiStream) Add(sr string) (<-chan models.Submission, error) {
+	ch := make(chan models.Submission, 100)
+	if err := m.addRoute(sr, func(r *multiRoute) { r.channels = append(r.channels, ch) }); err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// Handle starts streaming sr (if it isn't already) and calls f for each of its items.
+// f is called from the polling goroutine, so it should return quickly.
+func (m *MultiStream) Handle(sr string, f func(models.Submission)) error {
+	return m.addRoute(sr, func(r *multiRoute) { r.handlers = append(r.handlers, f) })
+}
+
+// Remove stops streaming sr. Its channels are closed before the next poll.
+func (m *MultiStream) Remove(sr string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToLower(sr)
+	if r, ok := m.routes[key]; ok {
+		delete(m.routes, key)
+		delete(m.failures, key)
+		m.removed = append(m.removed, r)
+	}
+}
+
+// Subreddits returns the names of all streamed subreddits.
+func (m *MultiStream) Subreddits() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ret := make([]string, 0, len(m.routes))
+	for _, r := range m.routes {
+		ret = append(ret, r.name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func (m *MultiStream) addRoute(sr string, add func(*multiRoute)) error {
+	if strings.ContainsAny(sr, "+/") {
+		return fmt.Errorf("'%s' is not a single subreddit", sr)
+	}
+	key := strings.ToLower(sr)
+	m.mu.Lock()
+	r, ok := m.routes[key]
+	m.mu.Unlock()
+	if !ok {
+		if _, err := m.c.getSubredditPosts(sr, "new", "all", 1); err != nil {
+			return err
+		}
+		r = &multiRoute{name: sr}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.routes[key]; ok {
+		r = existing
+	}
+	add(r)
+	m.routes[key] = r
+	return nil
+}
+
+// groups splits all subreddits into combined names not longer than maxGroupLength.
+// Subreddits which failed recently get a group of their own.
+func (m *MultiStream) groups() []string {
+	names := m.Subreddits()
+	ret := []string{}
+	group := ""
+	for _, name := range names {
+		if m.failureCount(name) > 0 {
+			ret = append(ret, name)
+			continue
+		}
+		if group != "" && len(group)+1+len(name) > maxGroupLength {
+			ret = append(ret, group)
+			group = ""
+		}
+		if group != "" {
+			group += "+"
+		}
+		group += name
+	}
+	if group != "" {
+		ret = append(ret, group)
+	}
+	return ret
+}
+
+func (m *MultiStream) failureCount(sr string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.failures[strings.ToLower(sr)]
+}
+
+// groupFailed checks which subreddits of a group that couldn't be fetched are broken.
+// Broken subreddits get a group of their own & are removed once they failed multiMaxFailures times in a row.
+func (m *MultiStream) groupFailed(group string, err error) {
+	names := strings.Split(group, "+")
+	for _, sr := range names {
+		srErr := err
+		if len(names) > 1 {
+			if _, srErr = m.c.getSubredditPosts(sr, "new", "all", 1); srErr == nil {
+				continue
+			}
+		}
+		key := strings.ToLower(sr)
+		m.mu.Lock()
+		if _, ok := m.routes[key]; !ok {
+			// Removed in the meantime.
+			m.mu.Unlock()
+			continue
+		}
+		m.failures[key]++
+		removed := m.failures[key] >= multiMaxFailures
+		m.mu.Unlock()
+		if removed {
+			m.Remove(sr)
+		}
+		select {
+		case m.errC <- &MultiStreamError{Subreddit: sr, Removed: removed, Err: srErr}:
+		default:
+		}
+	}
+}
+
+func (m *MultiStream) closeRemoved() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.removed {
+		for _, ch := range r.channels {
+			close(ch)
+		}
+	}
+	m.removed = nil
+}
+
+func (m *MultiStream) route(s models.Submission) {
+	m.mu.Lock()
+	r, ok := m.routes[strings.ToLower(s.GetSubreddit())]
+	var channels []chan models.Submission
+	var handlers []func(models.Submission)
+	if ok {
+		channels = append(channels, r.channels...)
+		handlers = append(handlers, r.handlers...)
+	}
+	m.mu.Unlock()
+
+	// Only the polling goroutine closes channels (see closeRemoved), so sending outside the lock is safe.
+	// A consumer which stopped reading blocks the poller, but never keeps the stream from closing.
+	for _, ch := range channels {
+		select {
+		case ch <- s:
+		case <-m.Close:
+			return
+		}
+	}
+	for _, f := range handlers {
+		f(s)
+	}
+}
+
+func (m *MultiStream) run() {
+	atomic.AddInt32(&m.c.streams, 1)
+	defer atomic.AddInt32(&m.c.streams, -1)
+	defer func() {
+		m.mu.Lock()
+		for key, r := range m.routes {
+			delete(m.routes, key)
+			m.removed = append(m.removed, r)
+		}
+		m.mu.Unlock()
+		m.closeRemoved()
+		close(m.errC)
+	}()
+
+	seen := newSeenIDs(100)
+	last := make(map[string]models.RedditID)
+	for {
+		select {
+		case <-m.Close:
+			return
+		default:
+		}
+		m.closeRemoved()
+
+		groups := m.groups()
+		seen.grow(100 * (len(groups) + 1))
+		active := make(map[string]models.RedditID, len(groups))
+		found := 0
+		for _, group := range groups {
+			params := map[string]string{
+				"limit":  "100",
+				"before": string(last[group]),
+			}
+			for k, v := range m.params {
+				params[k] = v
+			}
+			list, err := m.c.miraRequestListing("GET", RedditOauth+"/r/"+group+m.target, params)
+			if err != nil {
+				// A single failing group shouldn't stop the others. Start over at the top next time.
+				m.groupFailed(group, err)
+				continue
+			}
+			if !strings.Contains(group, "+") {
+				m.mu.Lock()
+				delete(m.failures, strings.ToLower(group))
+				m.mu.Unlock()
+			}
+			n := len(list.Children)
+			// The poll overlaps with the last one (see before above), so only count what is new.
+			sent := 0
+			for i := n - 1; i >= 0; i-- {
+				s, ok := list.Children[i].Data.(models.Submission)
+				if !ok || !seen.add(s.GetID()) {
+					continue
+				}
+				m.route(s)
+				sent++
+			}
+			if n > 2 {
+				active[group] = list.Children[1].Data.GetID()
+			} else if n > 0 {
+				active[group] = last[group]
+			}
+			if sent > found {
+				found = sent
+			}
+		}
+		// Groups change when subreddits are added or removed, forget their anchors.
+		last = active
+
+		m.c.waitStream(m.Interval.next(found, 100), m.Close)
+	}
+}
+
+// seenIDs remembers the last IDs added to it.
+type seenIDs struct {
+	max   int
+	ids   map[models.RedditID]bool
+	order []models.RedditID
+}
+
+func newSeenIDs(max int) *seenIDs {
+	return &seenIDs{
+		max: max,
+		ids: make(map[models.RedditID]bool),
+	}
+}
+
+// grow makes room for at least max IDs.
+func (s *seenIDs) grow(max int) {
+	if max > s.max {
+		s.max = max
+	}
+}
+
+// add remembers id and returns false if it was already known.
+func (s *seenIDs) add(id models.RedditID) bool {
+	if s.ids[id] {
+		return false
+	}
+	s.ids[id] = true
+	s.order = append(s.order, id)
+	for len(s.order) > s.max {
+		delete(s.ids, s.order[0])
+		s.order = s.order[1:]
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package mira
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+func TestMultiStreamClosesWithBlockedConsumer(t *testing.T) {
+	ids := make([]string, 150)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("c%d", i)
+	}
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, listingJSON(ids...))
+	}))
+
+	c.Config.CommentStreamInterval = 1
+	m, err := c.NewMultiStream(models.KComment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Never read from ch, so routing blocks once its buffer is full.
+	ch, err := m.Add("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.After(5 * time.Second)
+	for len(ch) < cap(ch) {
+		select {
+		case <-deadline:
+			t.Fatal("the stream didn't fill the channel")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	close(m.Close)
+	select {
+	case _, ok := <-m.Errors:
+		if ok {
+			t.Error("got an error, want Errors to be closed")
+		}
+	case <-deadline:
+		t.Fatal("the stream didn't stop while a consumer was blocked")
+	}
+	for range ch {
+	}
+}
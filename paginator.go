@@ -0,0 +1,86 @@
+package mira
+
+import (
+	"strconv"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// ListingPaginator walks through a listing page by page. Create one using Reddit.Paginate().
+type ListingPaginator struct {
+	c      *Reddit
+	target string
+	params map[string]string
+	anchor models.RedditID
+	newer  bool
+	limit  int
+	done   bool
+}
+
+// Paginate returns a ListingPaginator for any listing endpoint (e.g. RedditOauth + "/r/pics/new.json").
+// It starts at anchor, which can be empty to start at the top of the listing.
+//
+// By default it walks towards older items. If newer is set, it walks towards newer items instead and
+// returns each page oldest first. This is useful to catch up on everything after a known item.
+//
+// The page size can be set via params["limit"], it defaults to 100.
+func (c *Reddit) Paginate(target string, params map[string]string, anchor models.RedditID, newer bool) *ListingPaginator {
+	p := &ListingPaginator{
+		c:      c,
+		target: target,
+		params: map[string]string{"limit": "100"},
+		anchor: anchor,
+		newer:  newer,
+	}
+	for k, v := range params {
+		p.params[k] = v
+	}
+	p.limit, _ = strconv.Atoi(p.params["limit"])
+	if p.limit <= 0 {
+		p.limit = 100
+		p.params["limit"] = "100"
+	}
+	return p
+}
+
+// Done tells you if the end of the listing has been reached.
+func (p *ListingPaginator) Done() bool { return p.done }
+
+// Next returns the next page of the listing.
+func (p *ListingPaginator) Next() ([]models.RedditThing, error) {
+	if p.done {
+		return []models.RedditThing{}, nil
+	}
+	delete(p.params, "after")
+	delete(p.params, "before")
+	if p.anchor != "" {
+		if p.newer {
+			p.params["before"] = string(p.anchor)
+		} else {
+			p.params["after"] = string(p.anchor)
+		}
+	}
+	list, err := p.c.miraRequestListing("GET", p.target, p.params)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]models.RedditThing, 0, len(list.Children))
+	for _, child := range list.Children {
+		ret = append(ret, child.Data)
+	}
+
+	if p.newer {
+		for i, j := 0, len(ret)-1; i < j; i, j = i+1, j-1 {
+			ret[i], ret[j] = ret[j], ret[i]
+		}
+		if len(ret) > 0 {
+			p.anchor = ret[len(ret)-1].GetID()
+		}
+		p.done = len(ret) < p.limit
+	} else {
+		p.anchor = models.RedditID(list.After)
+		p.done = list.After == ""
+	}
+	return ret, nil
+}
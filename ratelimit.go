@@ -0,0 +1,64 @@
+package mira
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RateLimit is the request budget reddit reports with each response.
+type RateLimit struct {
+	Used      int
+	Remaining float64
+	Reset     time.Time
+}
+
+type rateLimit struct {
+	mu sync.Mutex // guards l
+	l  RateLimit
+}
+
+// RateLimit returns the request budget as reported by the last response from reddit.
+// It is empty until the first request has been made.
+func (c *Reddit) RateLimit() RateLimit {
+	c.rateLimit.mu.Lock()
+	defer c.rateLimit.mu.Unlock()
+	return c.rateLimit.l
+}
+
+func (c *Reddit) updateRateLimit(h http.Header) {
+	remaining, err := strconv.ParseFloat(h.Get("X-Ratelimit-Remaining"), 64)
+	if err != nil {
+		return
+	}
+	used, _ := strconv.Atoi(h.Get("X-Ratelimit-Used"))
+	reset, _ := strconv.Atoi(h.Get("X-Ratelimit-Reset"))
+
+	c.rateLimit.mu.Lock()
+	defer c.rateLimit.mu.Unlock()
+	c.rateLimit.l = RateLimit{
+		Used:      used,
+		Remaining: remaining,
+		Reset:     time.Now().Add(time.Duration(reset) * time.Second),
+	}
+}
+
+// budgetInterval returns how long each running stream has to wait between two polls
+// so all of them together stay within the remaining request budget.
+func (c *Reddit) budgetInterval() time.Duration {
+	rl := c.RateLimit()
+	until := time.Until(rl.Reset)
+	if until <= 0 {
+		return 0
+	}
+	if rl.Remaining < 1 {
+		return until
+	}
+	streams := atomic.LoadInt32(&c.streams)
+	if streams < 1 {
+		streams = 1
+	}
+	return time.Duration(float64(until) / rl.Remaining * float64(streams))
+}
@@ -32,11 +32,30 @@ func (c *Reddit) MiraRequest(method string, target string, payload map[string]st
 	if err != nil {
 		return nil, err
 	}
+	return c.do(r)
+}
+
+// miraRequestJSON sends payload JSON encoded, as needed by some newer API endpoints.
+func (c *Reddit) miraRequestJSON(method string, target string, payload interface{}) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	r, err := http.NewRequest(method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Add("Content-Type", "application/json")
+	return c.do(r)
+}
+
+func (c *Reddit) do(r *http.Request) ([]byte, error) {
 	response, err := c.Client.Do(r)
 	if err != nil {
 		return nil, err
 	}
 	defer response.Body.Close()
+	c.updateRateLimit(response.Header)
 	buf := new(bytes.Buffer)
 	buf.ReadFrom(response.Body)
 	data := buf.Bytes()
@@ -72,6 +91,11 @@ func (c *Reddit) Subreddit(name ...string) *Reddit {
 	return c.addQueue(strings.Join(name, "+"), models.KSubreddit)
 }
 
+// ModMail queues up the next action to be about a certain new modmail conversation.
+func (c *Reddit) ModMail(id string) *Reddit {
+	return c.addQueue(id, models.KModMail)
+}
+
 // Post queues up the next action to be about a certain Post.
 func (c *Reddit) Post(name string) *Reddit {
 	return c.addQueue(name, models.KPost)
@@ -82,6 +106,11 @@ func (c *Reddit) Comment(name string) *Reddit {
 	return c.addQueue(name, models.KComment)
 }
 
+// Message queues up the next action to be about a certain private message.
+func (c *Reddit) Message(name string) *Reddit {
+	return c.addQueue(name, models.KMessage)
+}
+
 // Redditor queues up the next action to be about a certain Redditor.
 func (c *Reddit) Redditor(name string) *Reddit {
 	return c.addQueue(name, models.KRedditor)
@@ -236,3 +265,27 @@ func findRedditError(data []byte) error {
 	}
 	return nil
 }
+
+// findAPIErrors returns the errors reddit reports in json.errors when called with api_type=json.
+func findAPIErrors(data []byte) error {
+	object := &struct {
+		JSON struct {
+			Errors models.APIErrors `json:"errors"`
+		} `json:"json"`
+	}{}
+	json.Unmarshal(data, object)
+	if len(object.JSON.Errors) > 0 {
+		return object.JSON.Errors
+	}
+	return nil
+}
+
+// postAPI posts params to target & returns the errors reddit reports in json.errors.
+func (c *Reddit) postAPI(target string, params map[string]string) error {
+	params["api_type"] = "json"
+	ans, err := c.MiraRequest("POST", target, params)
+	if err != nil {
+		return err
+	}
+	return findAPIErrors(ans)
+}
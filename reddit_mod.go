@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/ttgmpsn/mira/models"
 )
@@ -16,11 +17,9 @@ func (c *Reddit) Approve() error {
 		return err
 	}
 	target := RedditOauth + "/api/approve"
-	_, err = c.MiraRequest("POST", target, map[string]string{
-		"id":       name,
-		"api_type": "json",
+	return c.postAPI(target, map[string]string{
+		"id": name,
 	})
-	return err
 }
 
 // Remove mod-removes the last queued object. To remove own comments,
@@ -32,50 +31,227 @@ func (c *Reddit) Remove(spam bool) error {
 		return err
 	}
 	target := RedditOauth + "/api/remove"
-	_, err = c.MiraRequest("POST", target, map[string]string{
-		"id":       name,
-		"spam":     strconv.FormatBool(spam),
-		"api_type": "json",
+	return c.postAPI(target, map[string]string{
+		"id":   name,
+		"spam": strconv.FormatBool(spam),
 	})
-	return err
 }
 
-// Distinguish the last queued object.
-// Valid objects: Comment
+// Distinguish the last queued object. sticky is only used for comments & pins them
+// to the top of the post.
+// Valid objects: Comment, Post
 func (c *Reddit) Distinguish(how string, sticky bool) error {
-	name, _, err := c.checkType(models.KComment)
+	name, ttype, err := c.checkType(models.KComment, models.KPost)
 	if err != nil {
 		return err
 	}
+	params := map[string]string{
+		"id":  name,
+		"how": how,
+	}
+	if ttype == models.KComment {
+		params["sticky"] = strconv.FormatBool(sticky)
+	}
 	target := RedditOauth + "/api/distinguish"
-	_, err = c.MiraRequest("POST", target, map[string]string{
-		"id":       name,
-		"how":      how,
-		"sticky":   strconv.FormatBool(sticky),
-		"api_type": "json",
-	})
-	return err
+	return c.postAPI(target, params)
+}
+
+// Lock the last queued object, so no new comments can be made.
+// Valid objects: Comment, Post
+func (c *Reddit) Lock() error {
+	return c.modAction("/api/lock", nil, models.KComment, models.KPost)
+}
+
+// Unlock the last queued object.
+// Valid objects: Comment, Post
+func (c *Reddit) Unlock() error {
+	return c.modAction("/api/unlock", nil, models.KComment, models.KPost)
+}
+
+// Sticky the last queued object to the top of the subreddit. slot is the position (1-4),
+// 0 puts it in the last slot.
+// Valid objects: Post
+func (c *Reddit) Sticky(slot int) error {
+	params := map[string]string{
+		"state": "true",
+	}
+	if slot > 0 {
+		params["num"] = strconv.Itoa(slot)
+	}
+	return c.modAction("/api/set_subreddit_sticky", params, models.KPost)
+}
+
+// Unsticky the last queued object.
+// Valid objects: Post
+func (c *Reddit) Unsticky() error {
+	return c.modAction("/api/set_subreddit_sticky", map[string]string{
+		"state": "false",
+	}, models.KPost)
+}
+
+// MarkNSFW marks the last queued object as NSFW.
+// Valid objects: Post
+func (c *Reddit) MarkNSFW() error {
+	return c.modAction("/api/marknsfw", nil, models.KPost)
+}
+
+// UnmarkNSFW removes the NSFW mark from the last queued object.
+// Valid objects: Post
+func (c *Reddit) UnmarkNSFW() error {
+	return c.modAction("/api/unmarknsfw", nil, models.KPost)
+}
+
+// Spoiler marks the last queued object as spoiler.
+// Valid objects: Post
+func (c *Reddit) Spoiler() error {
+	return c.modAction("/api/spoiler", nil, models.KPost)
+}
+
+// Unspoiler removes the spoiler mark from the last queued object.
+// Valid objects: Post
+func (c *Reddit) Unspoiler() error {
+	return c.modAction("/api/unspoiler", nil, models.KPost)
+}
+
+// ContestMode enables or disables contest mode for the last queued object.
+// Valid objects: Post
+func (c *Reddit) ContestMode(enabled bool) error {
+	return c.modAction("/api/set_contest_mode", map[string]string{
+		"state": strconv.FormatBool(enabled),
+	}, models.KPost)
 }
 
-// UpdateSidebar of the last queued object.
+// SuggestedSort sets the default comment sort of the last queued object.
+// Sorting options: "confidence", "top", "new", "controversial", "old", "random", "qa", "live".
+// Pass an empty sort to use the subreddit default again.
+// Valid objects: Post
+func (c *Reddit) SuggestedSort(sort string) error {
+	if sort == "" {
+		sort = "blank"
+	}
+	return c.modAction("/api/set_suggested_sort", map[string]string{
+		"sort": sort,
+	}, models.KPost)
+}
+
+// IgnoreReports stops new reports on the last queued object from showing up in the mod queue.
+// Valid objects: Comment, Post
+func (c *Reddit) IgnoreReports() error {
+	return c.modAction("/api/ignore_reports", nil, models.KComment, models.KPost)
+}
+
+// UnignoreReports makes reports on the last queued object show up in the mod queue again.
+// Valid objects: Comment, Post
+func (c *Reddit) UnignoreReports() error {
+	return c.modAction("/api/unignore_reports", nil, models.KComment, models.KPost)
+}
+
+// modAction posts the ID of the last queued object & params to path.
+func (c *Reddit) modAction(path string, params map[string]string, kinds ...models.RedditKind) error {
+	name, _, err := c.checkType(kinds...)
+	if err != nil {
+		return err
+	}
+	payload := map[string]string{
+		"id": name,
+	}
+	for k, v := range params {
+		payload[k] = v
+	}
+	target := RedditOauth + path
+	return c.postAPI(target, payload)
+}
+
+// settingsKeys maps the names of settings returned by /about/edit to the ones expected by /api/site_admin.
+var settingsKeys = map[string]string{
+	"subreddit_type":    "type",
+	"content_options":   "link_type",
+	"default_set":       "allow_top",
+	"language":          "lang",
+	"subreddit_id":      "sr",
+	"header_hover_text": "header-title",
+}
+
+// settingsForm converts decoded JSON settings into form values, renaming them as given by keys.
+// nil values are sent empty, which reddit reads as "none". Nested objects can't be sent as form values & are skipped.
+func settingsForm(values map[string]interface{}, keys map[string]string) map[string]string {
+	params := map[string]string{}
+	for k, v := range values {
+		if key, ok := keys[k]; ok {
+			k = key
+		}
+		switch v := v.(type) {
+		case nil:
+			params[k] = ""
+		case string:
+			params[k] = v
+		case bool:
+			params[k] = strconv.FormatBool(v)
+		case float64:
+			params[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	return params
+}
+
+func (c *Reddit) getSubredditSettings(sr string) (*models.SubredditSettings, error) {
+	target := RedditOauth + "/r/" + sr + "/about/edit"
+	ans, err := c.MiraRequest("GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	ret := &struct {
+		Kind string                    `json:"kind"`
+		Data *models.SubredditSettings `json:"data"`
+	}{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	if ret.Data == nil {
+		return nil, fmt.Errorf("couldn't convert to SubredditSettings struct. Data has Kind '%s'", ret.Kind)
+	}
+	return ret.Data, nil
+}
+
+// SubredditSettings returns the settings of the last queued object.
 // Valid objects: Subreddit
-func (c *Reddit) UpdateSidebar(text string) error {
+func (c *Reddit) SubredditSettings() (*models.SubredditSettings, error) {
 	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	return c.getSubredditSettings(name)
+}
+
+// UpdateSettings reads the settings of the last queued object, lets update change them & writes them back.
+// All settings not touched by update are written back unchanged.
+// Valid objects: Subreddit
+func (c *Reddit) UpdateSettings(update func(*models.SubredditSettings)) error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	settings, err := c.getSubredditSettings(name)
+	if err != nil {
+		return err
+	}
+	update(settings)
+	values, err := settings.Values()
 	if err != nil {
 		return err
 	}
+
+	params := settingsForm(values, settingsKeys)
 	target := RedditOauth + "/api/site_admin"
-	_, err = c.MiraRequest("POST", target, map[string]string{
-		"sr":          name,
-		"name":        "None",
-		"description": text,
-		"title":       name,
-		"wikimode":    "anyone",
-		"link_type":   "any",
-		"type":        "public",
-		"api_type":    "json",
+	return c.postAPI(target, params)
+}
+
+// UpdateSidebar of the last queued object. All other settings are kept.
+// Valid objects: Subreddit
+func (c *Reddit) UpdateSidebar(text string) error {
+	return c.UpdateSettings(func(s *models.SubredditSettings) {
+		s.Description = text
 	})
-	return err
 }
 
 // ModQueue returns the mod queue from the last queued object.
@@ -138,21 +314,20 @@ func (c *Reddit) Ban(redditor string, days int, context, message, reason string)
 	if err != nil {
 		return err
 	}
-	args := map[string]string{
-		"name":        redditor,
+	return c.ban(subreddit, redditor, days, context, message, reason)
+}
+
+func (c *Reddit) ban(subreddit, redditor string, days int, context, message, reason string) error {
+	params := map[string]string{
 		"ban_context": context,
 		"ban_message": message,
 		"ban_reason":  reason,
 		"note":        reason,
-		"api_type":    "json",
-		"type":        "banned",
 	}
 	if days != 0 {
-		args["duration"] = strconv.Itoa(days)
+		params["duration"] = strconv.Itoa(days)
 	}
-	target := RedditOauth + "/r/" + subreddit + "/api/friend"
-	_, err = c.MiraRequest("POST", target, args)
-	return err
+	return c.friend(subreddit, RelBanned, redditor, params)
 }
 
 // GetModMailByID returns the ModMail Conversation for a given modmail ID
@@ -170,3 +345,83 @@ func (c *Reddit) GetModMailByID(conversationID string, markRead bool) (*models.N
 	}
 	return ret, nil
 }
+
+// ModMailState is used to filter modmail conversations by their state.
+type ModMailState string
+
+// List of all modmail states reddit allows to filter by
+const (
+	ModMailAll           ModMailState = "all"
+	ModMailNew           ModMailState = "new"
+	ModMailInProgress    ModMailState = "inprogress"
+	ModMailArchived      ModMailState = "archived"
+	ModMailHighlighted   ModMailState = "highlighted"
+	ModMailMod           ModMailState = "mod"
+	ModMailNotifications ModMailState = "notifications"
+	ModMailAppeals       ModMailState = "appeals"
+	ModMailJoinRequests  ModMailState = "join_requests"
+)
+
+// modMailUpdates is the part of a conversation listing needed to find out
+// which conversations changed.
+type modMailUpdates struct {
+	Conversations map[string]struct {
+		LastUpdated *time.Time `json:"lastUpdated"`
+	} `json:"conversations"`
+	ConversationIDs []string `json:"conversationIds"`
+}
+
+func (c *Reddit) getModMailUpdates(entity string, state ModMailState, after string, limit int) (*modMailUpdates, error) {
+	target := RedditOauth + "/api/mod/conversations"
+	ans, err := c.MiraRequest("GET", target, map[string]string{
+		"entity": entity,
+		"state":  string(state),
+		"sort":   "recent",
+		"limit":  strconv.Itoa(limit),
+		"after":  after,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &modMailUpdates{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// getModMailUpdatedSince returns the IDs of all conversations in entity (comma separated subreddit names,
+// empty for all moderated subreddits) that were updated after since, oldest first.
+// It also returns the newest update time found.
+func (c *Reddit) getModMailUpdatedSince(entity string, state ModMailState, since time.Time) ([]string, time.Time, error) {
+	newest := since
+	ids := []string{}
+	after := ""
+	for {
+		list, err := c.getModMailUpdates(entity, state, after, 100)
+		if err != nil {
+			return nil, since, err
+		}
+		done := len(list.ConversationIDs) < 100
+		for _, id := range list.ConversationIDs {
+			updated := list.Conversations[id].LastUpdated
+			if updated == nil || !updated.After(since) {
+				done = true
+				break
+			}
+			ids = append(ids, id)
+			if updated.After(newest) {
+				newest = *updated
+			}
+		}
+		if done {
+			break
+		}
+		after = list.ConversationIDs[len(list.ConversationIDs)-1]
+	}
+
+	for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+		ids[i], ids[j] = ids[j], ids[i]
+	}
+	return ids, newest, nil
+}
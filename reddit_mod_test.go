@@ -0,0 +1,65 @@
+package mira
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSettingsForm(t *testing.T) {
+	got := settingsForm(map[string]interface{}{
+		"title":                   "Pics",
+		"subreddit_type":          "public",
+		"over_18":                 false,
+		"comment_score_hide_mins": float64(60),
+		"wiki_edit_karma":         1.5,
+		"suggested_comment_sort":  nil,
+		"nested":                  map[string]interface{}{"a": "b"},
+	}, settingsKeys)
+	want := map[string]string{
+		"title":                   "Pics",
+		"type":                    "public",
+		"over_18":                 "false",
+		"comment_score_hide_mins": "60",
+		"wiki_edit_karma":         "1.5",
+		"suggested_comment_sort":  "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestUpdateSidebarKeepsSettings(t *testing.T) {
+	var posted map[string][]string
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/r/test/about/edit":
+			fmt.Fprint(w, `{"kind":"subreddit_settings","data":{"subreddit_id":"t5_abc","title":"Test","description":"old","header_hover_text":"hover","suggested_comment_sort":null}}`)
+		case "/api/site_admin":
+			if err := r.ParseForm(); err != nil {
+				t.Error(err)
+			}
+			posted = r.PostForm
+			fmt.Fprint(w, `{"json":{"errors":[]}}`)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+
+	if err := c.Subreddit("test").UpdateSidebar("new"); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"sr":                     "t5_abc",
+		"title":                  "Test",
+		"description":            "new",
+		"header-title":           "hover",
+		"suggested_comment_sort": "",
+	}
+	for k, v := range want {
+		if got, ok := posted[k]; !ok || len(got) != 1 || got[0] != v {
+			t.Errorf("%s = %v, want %q", k, got, v)
+		}
+	}
+}
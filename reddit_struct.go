@@ -42,10 +42,21 @@ type Credentials struct {
 //
 // Reddit.Config object
 //
-// The Reddit.Config controls some internal parameters. Currently, it has two options:
+// The Reddit.Config controls some internal parameters. Currently, it has these options:
 //  reddit.Config.CommentStreamInterval = 45
 //  reddit.Config.PostStreamInterval    = 45
-// The shown value is the default.
+//  reddit.Config.ModMailStreamInterval = 45
+//  reddit.Config.StreamMinInterval     = 0
+//  reddit.Config.StreamMaxInterval     = 0
+//  reddit.Config.Checkpointer          = nil
+//  reddit.Config.UploadClient          = nil
+// The shown value is the default. The stream intervals are in seconds. By default, streams poll at a
+// fixed interval. Set StreamMinInterval and/or StreamMaxInterval (e.g. 5 & 300) to let each stream start
+// at its interval & then adapt between the min & max interval, see StreamInterval. A bound of 0 means
+// the stream's own interval.
+// See Checkpointer on how to resume streams after a restart.
+// UploadClient is used to upload media files to reddit's storage, which is not part of the API & doesn't
+// need the API token. If it is nil, http.DefaultClient is used.
 type Reddit struct {
 	Client      *http.Client
 	creds       Credentials
@@ -56,11 +67,19 @@ type Reddit struct {
 
 	chain  chan *chainVals
 	Config redditConfig
+
+	rateLimit rateLimit
+	streams   int32 // number of running streams, used atomically
 }
 
 type redditConfig struct {
 	CommentStreamInterval int
 	PostStreamInterval    int
+	ModMailStreamInterval int
+	StreamMinInterval     int
+	StreamMaxInterval     int
+	Checkpointer          Checkpointer
+	UploadClient          *http.Client
 }
 
 type chainVals struct {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/ttgmpsn/mira/models"
 )
@@ -130,12 +131,10 @@ func (c *Reddit) UserFlair(user, text string) error {
 		return err
 	}
 	target := RedditOauth + "/r/" + name + "/api/flair"
-	_, err = c.MiraRequest("POST", target, map[string]string{
-		"name":     user,
-		"text":     text,
-		"api_type": "json",
+	return c.postAPI(target, map[string]string{
+		"name": user,
+		"text": text,
 	})
-	return err
 }
 
 // Wiki returns a wiki page from last queued object.
@@ -209,3 +208,37 @@ func (c *Reddit) Stylesheet() (*models.Stylesheet, error) {
 
 	return stylesheet, nil
 }
+
+// Subscribe to the last queued object. If several subreddits are queued, all of them are subscribed to.
+// Valid objects: Subreddit
+func (c *Reddit) Subscribe() error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	return c.subscribe("sub", name)
+}
+
+// Unsubscribe from the last queued object. If several subreddits are queued, all of them are unsubscribed from.
+// Valid objects: Subreddit
+func (c *Reddit) Unsubscribe() error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	return c.subscribe("unsub", name)
+}
+
+func (c *Reddit) subscribe(action, sr string) error {
+	params := map[string]string{
+		"action":  action,
+		"sr_name": strings.ReplaceAll(sr, "+", ","),
+	}
+	if action == "sub" {
+		// Don't subscribe to the default subreddits if this is the first subscription of the account.
+		params["skip_initial_defaults"] = "true"
+	}
+	target := RedditOauth + "/api/subscribe"
+	_, err := c.MiraRequest("POST", target, params)
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/ttgmpsn/mira/models"
 )
@@ -51,12 +52,29 @@ func (c *Reddit) getComment(id models.RedditID) (*models.Comment, error) {
 	return comment, nil
 }
 
-func (c *Reddit) getPostComments(postID models.RedditID, sort string, tdur string, limit int) ([]*models.Comment, error) {
-	if postID.Type() != models.KPost {
-		return nil, errors.New("the passed ID is not a post")
+// getInfo returns all things (up to 100) for the given IDs that are visible to you.
+func (c *Reddit) getInfo(ids []models.RedditID) ([]models.RedditThing, error) {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = string(id)
 	}
-	target := fmt.Sprintf("%s/comments/%s", RedditOauth, postID[3:])
-	ans, err := c.MiraRequest("GET", target, map[string]string{
+	target := RedditOauth + "/api/info.json"
+	list, err := c.miraRequestListing("GET", target, map[string]string{
+		"id": strings.Join(names, ","),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]models.RedditThing, 0, len(list.Children))
+	for _, child := range list.Children {
+		ret = append(ret, child.Data)
+	}
+	return ret, nil
+}
+
+func (c *Reddit) getPostComments(postID models.RedditID, sort string, tdur string, limit int) ([]*models.Comment, error) {
+	tree, err := c.getCommentTree(postID, map[string]string{
 		"sort":     sort,
 		"limit":    strconv.Itoa(limit),
 		"showmore": strconv.FormatBool(true),
@@ -65,26 +83,159 @@ func (c *Reddit) getPostComments(postID models.RedditID, sort string, tdur strin
 	if err != nil {
 		return nil, err
 	}
+	ret := []*models.Comment{}
+	for _, n := range tree.Replies {
+		ret = append(ret, n.Comment)
+	}
+	return ret, nil
+}
+
+// getCommentTree returns a post with all of its loaded comments.
+func (c *Reddit) getCommentTree(postID models.RedditID, params map[string]string) (*models.CommentTree, error) {
+	if postID.Type() != models.KPost {
+		return nil, errors.New("the passed ID is not a post")
+	}
+	target := fmt.Sprintf("%s/comments/%s", RedditOauth, postID[3:])
+	ans, err := c.MiraRequest("GET", target, params)
+	if err != nil {
+		return nil, err
+	}
 
 	rets := []*models.Response{}
 	if err = json.Unmarshal(ans, &rets); err != nil {
 		return nil, err
 	}
+	if len(rets) < 1 {
+		return nil, fmt.Errorf("no results")
+	}
+	var post *models.Post
+	if list, ok := rets[0].Data.(*models.Listing); ok && len(list.Children) > 0 {
+		post, _ = list.Children[0].Data.(*models.Post)
+	}
+	if post == nil {
+		return nil, fmt.Errorf("provided ID '%s' is no valid post", postID)
+	}
 	if len(rets) < 2 {
 		// not two elements --> no comments
-		return []*models.Comment{}, nil
+		return models.NewCommentTree(post, nil)
 	}
 	list, ok := rets[1].Data.(*models.Listing)
 	if !ok {
 		return nil, fmt.Errorf("couldn't convert to Listing struct. Data has Kind '%s'", rets[1].Kind)
 	}
-	ret := []*models.Comment{}
-	for _, comment := range list.Children {
-		if c, ok := comment.Data.(*models.Comment); ok {
-			ret = append(ret, c)
+	return models.NewCommentTree(post, list.Children)
+}
+
+// getMoreChildren loads the comments behind More stubs. Reddit allows up to 100 children per call.
+// The returned comments are not nested, add them to a CommentTree to place them.
+func (c *Reddit) getMoreChildren(linkID models.RedditID, children []string, sort string) ([]models.RedditElement, error) {
+	target := RedditOauth + "/api/morechildren"
+	ans, err := c.MiraRequest("GET", target, map[string]string{
+		"link_id":  string(linkID),
+		"children": strings.Join(children, ","),
+		"sort":     sort,
+		"api_type": "json",
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &struct {
+		JSON struct {
+			Data struct {
+				Things []models.RedditElement `json:"things"`
+			} `json:"data"`
+		} `json:"json"`
+	}{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	return ret.JSON.Data.Things, nil
+}
+
+// expandMore loads the comments behind a More stub into tree. At most 100 comments are loaded
+// per call, the stub is removed once all of its comments are loaded.
+func (c *Reddit) expandMore(tree *models.CommentTree, m *models.More, sort string) error {
+	if m.IsContinueThread() {
+		sub, err := c.getCommentTree(tree.Post.Name, map[string]string{
+			"sort":    sort,
+			"comment": string(m.ParentID[3:]),
+		})
+		if err != nil {
+			return err
 		}
+		tree.RemoveMore(m)
+		return tree.Add(subtreeElements(sub))
 	}
-	return ret, nil
+
+	n := len(m.Children)
+	if n > 100 {
+		n = 100
+	}
+	things, err := c.getMoreChildren(tree.Post.Name, m.Children[:n], sort)
+	if err != nil {
+		return err
+	}
+	m.Children = m.Children[n:]
+	if len(m.Children) == 0 {
+		tree.RemoveMore(m)
+	}
+	return tree.Add(things)
+}
+
+// subtreeElements turns a tree back into elements, parents first, so they can be added to another tree.
+func subtreeElements(t *models.CommentTree) []models.RedditElement {
+	ret := []models.RedditElement{}
+	for _, m := range t.More {
+		ret = append(ret, models.RedditElement{Kind: models.KMore, Data: m})
+	}
+	t.Walk(func(n *models.CommentNode) bool {
+		ret = append(ret, models.RedditElement{Kind: models.KComment, Data: n.Comment})
+		for _, m := range n.More {
+			ret = append(ret, models.RedditElement{Kind: models.KMore, Data: m})
+		}
+		return true
+	})
+	return ret
+}
+
+// CommentTree returns the last queued object together with all of its comments, nested like on reddit.
+// Comments that weren't loaded are represented by More stubs, use ExpandMore to load them.
+//
+// Sorting options: "confidence", "top", "new", "controversial", "old", "random", "qa", "live"
+//
+// Limit is the number of comments to load, at most 500.
+// Valid objects: Post
+func (c *Reddit) CommentTree(sort string, limit int) (*models.CommentTree, error) {
+	name, _, err := c.checkType(models.KPost)
+	if err != nil {
+		return nil, err
+	}
+	return c.getCommentTree(models.RedditID(name), map[string]string{
+		"sort":  sort,
+		"limit": strconv.Itoa(limit),
+	})
+}
+
+// ExpandMore loads the comments behind the More stubs of tree, using at most maxRequests requests.
+// Pass 0 to load the whole tree, which can take a lot of requests for big threads.
+func (c *Reddit) ExpandMore(tree *models.CommentTree, sort string, maxRequests int) error {
+	var last models.More
+	for i := 0; maxRequests <= 0 || i < maxRequests; i++ {
+		stubs := tree.MoreStubs()
+		if len(stubs) == 0 {
+			return nil
+		}
+		// Every expansion either shrinks or removes the stub, don't loop forever if it didn't.
+		m := stubs[0]
+		if i > 0 && m.Name == last.Name && m.ParentID == last.ParentID && len(m.Children) == len(last.Children) {
+			return fmt.Errorf("more stub %s of %s couldn't be expanded", m.Name, m.ParentID)
+		}
+		last = *m
+		if err := c.expandMore(tree, stubs[0], sort); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // GetParentPost returns the Post ID for the last queued object.
@@ -101,6 +252,69 @@ func (c *Reddit) GetParentPost() (models.RedditID, error) {
 	return info.LinkID, nil
 }
 
+// maxContextDepth is the highest number of parents reddit returns with a comment.
+const maxContextDepth = 8
+
+func checkContextDepth(depth int) error {
+	if depth < 0 || depth > maxContextDepth {
+		return fmt.Errorf("context depth must be between 0 and %d, got %d", maxContextDepth, depth)
+	}
+	return nil
+}
+
+// Context returns the last queued object together with its parents up to depth levels (0 to 8),
+// and its replies.
+// Reddit only returns a comment together with its post, which a comment ID doesn't tell. So this needs
+// an additional request to look the comment up. Use ContextWithID if you already know the post (e.g.
+// from Comment.LinkID), which takes a single request.
+// Valid objects: Comment
+func (c *Reddit) Context(depth int) (*models.CommentContext, error) {
+	name, _, err := c.checkType(models.KComment)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkContextDepth(depth); err != nil {
+		return nil, err
+	}
+	info, err := c.getComment(models.RedditID(name))
+	if err != nil {
+		return nil, err
+	}
+	return c.ContextWithID(info.LinkID, info.Name, depth)
+}
+
+// ContextWithID returns a comment together with its parents up to depth levels (0 to 8),
+// and its replies, without it needing to be queued up.
+func (c *Reddit) ContextWithID(postID, commentID models.RedditID, depth int) (*models.CommentContext, error) {
+	if commentID.Type() != models.KComment {
+		return nil, errors.New("the passed ID is not a comment")
+	}
+	if err := checkContextDepth(depth); err != nil {
+		return nil, err
+	}
+	tree, err := c.getCommentTree(postID, map[string]string{
+		"comment": string(commentID[3:]),
+		"context": strconv.Itoa(depth),
+	})
+	if err != nil {
+		return nil, err
+	}
+	node := tree.Find(commentID)
+	if node == nil {
+		return nil, fmt.Errorf("comment '%s' not found in post '%s'", commentID, postID)
+	}
+
+	ret := &models.CommentContext{
+		Post:      tree.Post,
+		Ancestors: []*models.Comment{},
+		Comment:   node,
+	}
+	for _, n := range node.Ancestors() {
+		ret.Ancestors = append(ret.Ancestors, n.Comment)
+	}
+	return ret, nil
+}
+
 // SubmissionInfo returns general information about the queued submission.
 func (c *Reddit) SubmissionInfo() (models.Submission, error) {
 	name, ttype := c.getQueue()
@@ -126,31 +340,122 @@ func (c *Reddit) SubmissionInfoID(name models.RedditID) (models.Submission, erro
 	}
 }
 
-// Submit submits a new Post to the last queued object.
+// SubmitKind is the type of a new post.
+type SubmitKind string
+
+// List of all kinds of posts that can be submitted via SubmitPost
+const (
+	SubmitSelf      SubmitKind = "self"
+	SubmitLink      SubmitKind = "link"
+	SubmitCrosspost SubmitKind = "crosspost"
+
+	// Used by SubmitImage & SubmitVideo, which need to upload the media first.
+	kindImage SubmitKind = "image"
+	kindVideo SubmitKind = "video"
+)
+
+// SubmitOptions contains all settings for a new post. Only the fields matching Kind are used.
+type SubmitOptions struct {
+	// Kind defaults to SubmitSelf.
+	Kind SubmitKind
+	// Text is the body of a self post.
+	Text string
+	// URL is the target of a link post.
+	URL string
+	// CrosspostFullname is the ID of the post to crosspost.
+	CrosspostFullname models.RedditID
+	FlairID           string
+	FlairText         string
+	NSFW              bool
+	Spoiler           bool
+	// DisableReplies turns off inbox replies for the post.
+	DisableReplies bool
+	// Resubmit allows posting a link that has already been posted to the subreddit.
+	Resubmit     bool
+	CollectionID string
+
+	posterURL string // thumbnail of video posts
+}
+
+// Submit submits a new self post to the last queued object.
 // Valid objects: Subreddit
 func (c *Reddit) Submit(title string, text string) (*models.PostActionResponse, error) {
-	ret := &models.PostActionResponse{}
 	name, _, err := c.checkType(models.KSubreddit)
 	if err != nil {
 		return nil, err
 	}
-	target := RedditOauth + "/api/submit"
-	ans, err := c.MiraRequest("POST", target, map[string]string{
-		"title":    title,
-		"sr":       name,
-		"text":     text,
-		"kind":     "self",
-		"resubmit": "true",
-		"api_type": "json",
-	})
-	json.Unmarshal(ans, ret)
-	return ret, err
+	return c.submit(name, title, SubmitOptions{Kind: SubmitSelf, Text: text, Resubmit: true})
+}
+
+// SubmitPost submits a new post to the last queued object and returns it.
+// Valid objects: Subreddit
+func (c *Reddit) SubmitPost(title string, opts SubmitOptions) (*models.Post, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := c.submit(name, title, opts)
+	if err != nil {
+		return nil, err
+	}
+	return c.getPost(ret.JSON.Data.Name)
+}
+
+func (c *Reddit) submit(sr, title string, opts SubmitOptions) (*models.PostActionResponse, error) {
+	if opts.Kind == "" {
+		opts.Kind = SubmitSelf
+	}
+	params := map[string]string{
+		"title":       title,
+		"sr":          sr,
+		"kind":        string(opts.Kind),
+		"nsfw":        strconv.FormatBool(opts.NSFW),
+		"spoiler":     strconv.FormatBool(opts.Spoiler),
+		"sendreplies": strconv.FormatBool(!opts.DisableReplies),
+		"resubmit":    strconv.FormatBool(opts.Resubmit),
+		"api_type":    "json",
+	}
+	switch opts.Kind {
+	case SubmitSelf:
+		params["text"] = opts.Text
+	case SubmitLink, kindImage:
+		params["url"] = opts.URL
+	case kindVideo:
+		params["url"] = opts.URL
+		params["video_poster_url"] = opts.posterURL
+	case SubmitCrosspost:
+		params["crosspost_fullname"] = string(opts.CrosspostFullname)
+	default:
+		return nil, fmt.Errorf("'%s' is not a valid kind of post", opts.Kind)
+	}
+	if opts.FlairID != "" {
+		params["flair_id"] = opts.FlairID
+	}
+	if opts.FlairText != "" {
+		params["flair_text"] = opts.FlairText
+	}
+	if opts.CollectionID != "" {
+		params["collection_id"] = opts.CollectionID
+	}
+
+	ret := &models.PostActionResponse{}
+	ans, err := c.MiraRequest("POST", RedditOauth+"/api/submit", params)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	if len(ret.JSON.Errors) > 0 {
+		return ret, ret.JSON.Errors
+	}
+	return ret, nil
 }
 
 // Reply adds a comment to the last queued object.
-// Valid objects: Comment, Post
+// Valid objects: Comment, Post, Message
 func (c *Reddit) Reply(text string) (*models.CommentActionResponse, error) {
-	name, _, err := c.checkType(models.KComment, models.KPost)
+	name, _, err := c.checkType(models.KComment, models.KPost, models.KMessage)
 	if err != nil {
 		return nil, err
 	}
@@ -166,23 +471,30 @@ func (c *Reddit) ReplyWithID(name, text string) (*models.CommentActionResponse,
 		"thing_id": name,
 		"api_type": "json",
 	})
+	if err != nil {
+		return ret, err
+	}
 	json.Unmarshal(ans, ret)
-	return ret, err
+	if len(ret.JSON.Errors) > 0 {
+		return ret, ret.JSON.Errors
+	}
+	return ret, nil
 }
 
-// Delete the last queued object.
-// Valid objects: Comment, Post
+// Delete the last queued object. Messages are only deleted from your inbox.
+// Valid objects: Comment, Post, Message
 func (c *Reddit) Delete() error {
-	name, _, err := c.checkType(models.KComment, models.KPost)
+	name, ttype, err := c.checkType(models.KComment, models.KPost, models.KMessage)
 	if err != nil {
 		return err
 	}
 	target := RedditOauth + "/api/del"
-	_, err = c.MiraRequest("POST", target, map[string]string{
-		"id":       name,
-		"api_type": "json",
+	if ttype == models.KMessage {
+		target = RedditOauth + "/api/del_msg"
+	}
+	return c.postAPI(target, map[string]string{
+		"id": name,
 	})
-	return err
 }
 
 // Edit the last queued object.
@@ -213,10 +525,139 @@ func (c *Reddit) SelectFlair(text string) error {
 		return err
 	}
 	target := RedditOauth + "/api/selectflair"
+	return c.postAPI(target, map[string]string{
+		"link": name,
+		"text": text,
+	})
+}
+
+// VoteDirection is the direction of a vote.
+type VoteDirection int
+
+// List of all vote directions
+const (
+	Upvote   VoteDirection = 1
+	NoVote   VoteDirection = 0
+	Downvote VoteDirection = -1
+)
+
+// Vote on the last queued object. Use NoVote to remove an earlier vote.
+// Valid objects: Comment, Post
+func (c *Reddit) Vote(dir VoteDirection) error {
+	name, _, err := c.checkType(models.KComment, models.KPost)
+	if err != nil {
+		return err
+	}
+	target := RedditOauth + "/api/vote"
 	_, err = c.MiraRequest("POST", target, map[string]string{
-		"link":     name,
-		"text":     text,
-		"api_type": "json",
+		"id":  name,
+		"dir": strconv.Itoa(int(dir)),
 	})
 	return err
 }
+
+// Save the last queued object. category is only available to reddit premium users & can be left empty.
+// Valid objects: Comment, Post
+func (c *Reddit) Save(category string) error {
+	name, _, err := c.checkType(models.KComment, models.KPost)
+	if err != nil {
+		return err
+	}
+	target := RedditOauth + "/api/save"
+	_, err = c.MiraRequest("POST", target, map[string]string{
+		"id":       name,
+		"category": category,
+	})
+	return err
+}
+
+// Unsave the last queued object.
+// Valid objects: Comment, Post
+func (c *Reddit) Unsave() error {
+	name, _, err := c.checkType(models.KComment, models.KPost)
+	if err != nil {
+		return err
+	}
+	target := RedditOauth + "/api/unsave"
+	_, err = c.MiraRequest("POST", target, map[string]string{
+		"id": name,
+	})
+	return err
+}
+
+// Hide the last queued object from your listings.
+// Valid objects: Post
+func (c *Reddit) Hide() error {
+	name, _, err := c.checkType(models.KPost)
+	if err != nil {
+		return err
+	}
+	return c.HidePosts(models.RedditID(name))
+}
+
+// Unhide the last queued object.
+// Valid objects: Post
+func (c *Reddit) Unhide() error {
+	name, _, err := c.checkType(models.KPost)
+	if err != nil {
+		return err
+	}
+	return c.UnhidePosts(models.RedditID(name))
+}
+
+// HidePosts hides multiple posts, without them needing to be queued up.
+func (c *Reddit) HidePosts(ids ...models.RedditID) error {
+	return c.hideAction("/api/hide", ids)
+}
+
+// UnhidePosts unhides multiple posts, without them needing to be queued up.
+func (c *Reddit) UnhidePosts(ids ...models.RedditID) error {
+	return c.hideAction("/api/unhide", ids)
+}
+
+func (c *Reddit) hideAction(path string, ids []models.RedditID) error {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		if id.Type() != models.KPost {
+			return fmt.Errorf("'%s' is not a post", id)
+		}
+		names[i] = string(id)
+	}
+	target := RedditOauth + path
+	_, err := c.MiraRequest("POST", target, map[string]string{
+		"id": strings.Join(names, ","),
+	})
+	return err
+}
+
+// Report the last queued object to the moderators with a free text reason.
+// Valid objects: Comment, Post, Message
+func (c *Reddit) Report(reason string) error {
+	name, _, err := c.checkType(models.KComment, models.KPost, models.KMessage)
+	if err != nil {
+		return err
+	}
+	return c.report(name, map[string]string{
+		"reason": reason,
+	})
+}
+
+// ReportRule reports the last queued object to the moderators for breaking a subreddit rule.
+// rule is the short name of the rule, as shown in the report menu.
+// Valid objects: Comment, Post
+func (c *Reddit) ReportRule(rule string) error {
+	name, _, err := c.checkType(models.KComment, models.KPost)
+	if err != nil {
+		return err
+	}
+	return c.report(name, map[string]string{
+		"reason":      rule,
+		"rule_reason": rule,
+	})
+}
+
+func (c *Reddit) report(name string, params map[string]string) error {
+	params["thing_id"] = name
+	target := RedditOauth + "/api/report"
+	return c.postAPI(target, params)
+}
@@ -0,0 +1,60 @@
+package mira
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// commentJSON returns a comment of post t3_p with the given replies (a listing or "").
+func commentJSON(id, parent, replies string) string {
+	if replies == "" {
+		replies = `""`
+	}
+	return fmt.Sprintf(`{"kind":"t1","data":{"id":"%s","name":"t1_%s","parent_id":"%s","link_id":"t3_p","replies":%s}}`, id, id, parent, replies)
+}
+
+func TestContextWithID(t *testing.T) {
+	requests := 0
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		q := r.URL.Query()
+		if r.URL.Path != "/comments/p" || q.Get("comment") != "c" || q.Get("context") != "2" {
+			t.Errorf("unexpected request to %s", r.URL)
+		}
+		// a > b > c > d
+		repliesC := `{"kind":"Listing","data":{"children":[` + commentJSON("d", "t1_c", "") + `]}}`
+		repliesB := `{"kind":"Listing","data":{"children":[` + commentJSON("c", "t1_b", repliesC) + `]}}`
+		repliesA := `{"kind":"Listing","data":{"children":[` + commentJSON("b", "t1_a", repliesB) + `]}}`
+		fmt.Fprint(w, `[{"kind":"Listing","data":{"children":[{"kind":"t3","data":{"name":"t3_p"}}]}},
+			{"kind":"Listing","data":{"children":[`+commentJSON("a", "t3_p", repliesA)+`]}}]`)
+	}))
+
+	ctx, err := c.ContextWithID("t3_p", "t1_c", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("got %d requests, want 1", requests)
+	}
+	if len(ctx.Ancestors) != 2 || ctx.Ancestors[0].Name != "t1_a" || ctx.Ancestors[1].Name != "t1_b" {
+		t.Errorf("got ancestors %+v", ctx.Ancestors)
+	}
+	if ctx.Comment.Comment.Name != "t1_c" || len(ctx.Comment.Replies) != 1 || !ctx.IsComplete() {
+		t.Errorf("got comment %+v", ctx.Comment)
+	}
+}
+
+func TestContextDepth(t *testing.T) {
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL)
+	}))
+	for _, depth := range []int{-1, 9} {
+		if _, err := c.ContextWithID("t3_p", "t1_c", depth); err == nil {
+			t.Errorf("ContextWithID accepted depth %d", depth)
+		}
+		if _, err := c.Comment("t1_c").Context(depth); err == nil {
+			t.Errorf("Context accepted depth %d", depth)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/ttgmpsn/mira/models"
 )
@@ -167,24 +168,21 @@ func (c *Reddit) Compose(subject, text string) error {
 		return err
 	}
 	target := RedditOauth + "/api/compose"
-	_, err = c.MiraRequest("POST", target, map[string]string{
-		"subject":  subject,
-		"text":     text,
-		"to":       name,
-		"api_type": "json",
+	return c.postAPI(target, map[string]string{
+		"subject": subject,
+		"text":    text,
+		"to":      name,
 	})
-	return err
 }
 
 // ReadMessage marks a message for the last queued object as read.
 // Valid objects: Me
 func (c *Reddit) ReadMessage(messageID string) error {
-	_, _, err := c.checkType("me")
-	if err != nil {
-		return err
+	if _, ttype := c.getQueue(); ttype != "me" {
+		return fmt.Errorf("'%s' type does not have an option to read messages", ttype)
 	}
 	target := RedditOauth + "/api/read_message"
-	_, err = c.MiraRequest("POST", target, map[string]string{
+	_, err := c.MiraRequest("POST", target, map[string]string{
 		"id": messageID,
 	})
 	return err
@@ -193,30 +191,150 @@ func (c *Reddit) ReadMessage(messageID string) error {
 // ReadAllMessages marks all message for the last queued object as read.
 // Valid objects: Me
 func (c *Reddit) ReadAllMessages() error {
-	_, _, err := c.checkType("me")
-	if err != nil {
-		return err
+	if _, ttype := c.getQueue(); ttype != "me" {
+		return fmt.Errorf("'%s' type does not have an option to read messages", ttype)
 	}
 	target := RedditOauth + "/api/read_all_messages"
-	_, err = c.MiraRequest("POST", target, nil)
+	_, err := c.MiraRequest("POST", target, nil)
 	return err
 }
 
 // ListUnreadMessages for the last queued object.
 // Valid objects: Me
-func (c *Reddit) ListUnreadMessages() ([]*models.Comment, error) {
-	_, _, err := c.checkType("me")
+func (c *Reddit) ListUnreadMessages() ([]*models.Message, error) {
+	return c.Inbox(InboxUnread, 100)
+}
+
+// InboxListing selects one of the listings of your inbox.
+type InboxListing string
+
+// List of all inbox listings
+const (
+	InboxAll            InboxListing = "inbox"
+	InboxUnread         InboxListing = "unread"
+	InboxSent           InboxListing = "sent"
+	InboxMessages       InboxListing = "messages"
+	InboxMentions       InboxListing = "mentions"
+	InboxCommentReplies InboxListing = "comments"
+	InboxPostReplies    InboxListing = "selfreply"
+)
+
+// Inbox returns messages from one of the inbox listings of the last queued object.
+// Comment replies & mentions are returned as Messages as well (with WasComment set).
+// Reading messages does not mark them as read.
+// Valid objects: Me
+func (c *Reddit) Inbox(listing InboxListing, limit int) ([]*models.Message, error) {
+	return c.InboxAfter(listing, "", limit)
+}
+
+// InboxAfter returns messages from one of the inbox listings of the last queued object after a given item.
+// Valid objects: Me
+func (c *Reddit) InboxAfter(listing InboxListing, last models.RedditID, limit int) ([]*models.Message, error) {
+	if _, ttype := c.getQueue(); ttype != "me" {
+		return nil, fmt.Errorf("'%s' type does not have an option for the inbox", ttype)
+	}
+	target := RedditOauth + "/message/" + string(listing)
+	ans, err := c.MiraRequest("GET", target, map[string]string{
+		"limit": strconv.Itoa(limit),
+		"after": string(last),
+		"mark":  "false",
+	})
 	if err != nil {
 		return nil, err
 	}
-	target := RedditOauth + "/message/unread"
-	ans, err := c.MiraRequest("GET", target, map[string]string{
-		"mark": "false",
+	list := &struct {
+		Data models.MessageListing `json:"data"`
+	}{}
+	if err := json.Unmarshal(ans, list); err != nil {
+		return nil, err
+	}
+
+	ret := []*models.Message{}
+	for _, child := range list.Data.Children {
+		if child.Data != nil {
+			ret = append(ret, child.Data)
+		}
+	}
+	return ret, nil
+}
+
+// MarkRead marks the last queued object as read.
+// Valid objects: Message, Comment (inbox replies & mentions), ModMail
+func (c *Reddit) MarkRead() error {
+	name, ttype, err := c.checkType(models.KMessage, models.KComment, models.KModMail)
+	if err != nil {
+		return err
+	}
+	if ttype == models.KModMail {
+		return c.MarkModMailRead(name)
+	}
+	return c.MarkMessagesRead(models.RedditID(name))
+}
+
+// MarkUnread marks the last queued object as unread.
+// Valid objects: Message, Comment (inbox replies & mentions), ModMail
+func (c *Reddit) MarkUnread() error {
+	name, ttype, err := c.checkType(models.KMessage, models.KComment, models.KModMail)
+	if err != nil {
+		return err
+	}
+	if ttype == models.KModMail {
+		return c.MarkModMailUnread(name)
+	}
+	return c.MarkMessagesUnread(models.RedditID(name))
+}
+
+// MarkMessagesRead marks multiple messages as read, without them needing to be queued up.
+func (c *Reddit) MarkMessagesRead(ids ...models.RedditID) error {
+	return c.messageAction("/api/read_message", ids)
+}
+
+// MarkMessagesUnread marks multiple messages as unread, without them needing to be queued up.
+func (c *Reddit) MarkMessagesUnread(ids ...models.RedditID) error {
+	return c.messageAction("/api/unread_message", ids)
+}
+
+// Collapse collapses the last queued object in your inbox.
+// Valid objects: Message
+func (c *Reddit) Collapse() error {
+	name, _, err := c.checkType(models.KMessage)
+	if err != nil {
+		return err
+	}
+	return c.messageAction("/api/collapse_message", []models.RedditID{models.RedditID(name)})
+}
+
+// Uncollapse uncollapses the last queued object in your inbox.
+// Valid objects: Message
+func (c *Reddit) Uncollapse() error {
+	name, _, err := c.checkType(models.KMessage)
+	if err != nil {
+		return err
+	}
+	return c.messageAction("/api/uncollapse_message", []models.RedditID{models.RedditID(name)})
+}
+
+// BlockAuthor blocks the author of the last queued object.
+// Valid objects: Message, Comment (inbox replies & mentions)
+func (c *Reddit) BlockAuthor() error {
+	name, _, err := c.checkType(models.KMessage, models.KComment)
+	if err != nil {
+		return err
+	}
+	target := RedditOauth + "/api/block"
+	return c.postAPI(target, map[string]string{
+		"id": name,
 	})
-	ret := []*models.Comment{}
+}
 
-	// :TODO: check reply type
-	fmt.Println(string(ans))
-	json.Unmarshal(ans, &ret)
-	return ret, err
+func (c *Reddit) messageAction(path string, ids []models.RedditID) error {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = string(id)
+	}
+	target := RedditOauth + path
+	_, err := c.MiraRequest("POST", target, map[string]string{
+		"id": strings.Join(names, ","),
+	})
+	return err
 }
@@ -0,0 +1,30 @@
+package mira
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestInboxMe(t *testing.T) {
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/message/unread" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"kind":"Listing","data":{"children":[{"kind":"t4","data":{"name":"t4_a","subject":"hi"}}]}}`)
+	}))
+
+	msgs, err := c.Me().ListUnreadMessages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 1 || msgs[0].Name != "t4_a" {
+		t.Errorf("got %+v, want message t4_a", msgs)
+	}
+	if _, err := c.Me().Inbox(InboxUnread, 10); err != nil {
+		t.Errorf("Inbox: %s", err)
+	}
+	if _, err := c.Redditor("spez").Inbox(InboxUnread, 10); err == nil {
+		t.Error("Inbox accepted a Redditor")
+	}
+}
@@ -0,0 +1,237 @@
+package mira
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// RelationshipType is the kind of user list of a subreddit.
+type RelationshipType string
+
+// List of all relationship types
+const (
+	RelBanned          RelationshipType = "banned"
+	RelMuted           RelationshipType = "muted"
+	RelContributor     RelationshipType = "contributor"
+	RelWikiBanned      RelationshipType = "wikibanned"
+	RelWikiContributor RelationshipType = "wikicontributor"
+	RelModerator       RelationshipType = "moderator"
+	RelModeratorInvite RelationshipType = "moderator_invite"
+)
+
+// relationshipListings maps relationship types to their about/ listing.
+var relationshipListings = map[RelationshipType]string{
+	RelBanned:          "banned",
+	RelMuted:           "muted",
+	RelContributor:     "contributors",
+	RelWikiBanned:      "wikibanned",
+	RelWikiContributor: "wikicontributors",
+	RelModerator:       "moderators",
+}
+
+// friend adds redditor to a user list of subreddit. If the relationship already exists, it is updated.
+func (c *Reddit) friend(subreddit string, rtype RelationshipType, redditor string, params map[string]string) error {
+	args := map[string]string{
+		"name": redditor,
+		"type": string(rtype),
+	}
+	for k, v := range params {
+		args[k] = v
+	}
+	target := RedditOauth + "/r/" + subreddit + "/api/friend"
+	return c.postAPI(target, args)
+}
+
+// unfriend removes redditor from a user list of subreddit.
+func (c *Reddit) unfriend(subreddit string, rtype RelationshipType, redditor string) error {
+	target := RedditOauth + "/r/" + subreddit + "/api/unfriend"
+	return c.postAPI(target, map[string]string{
+		"name": redditor,
+		"type": string(rtype),
+	})
+}
+
+// relationshipAction checks the queue & then adds or removes redditor from a user list.
+func (c *Reddit) relationshipAction(add bool, rtype RelationshipType, redditor string, params map[string]string) error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	if add {
+		return c.friend(name, rtype, redditor, params)
+	}
+	return c.unfriend(name, rtype, redditor)
+}
+
+// EditBan changes the duration, message & reason of an existing ban in the last queued object.
+// days = 0 makes the ban permanent.
+// Valid objects: Subreddit
+func (c *Reddit) EditBan(redditor string, days int, message, reason string) error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	return c.ban(name, redditor, days, "", message, reason)
+}
+
+// Unban unbans a redditor from the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) Unban(redditor string) error {
+	return c.relationshipAction(false, RelBanned, redditor, nil)
+}
+
+// Mute mutes a redditor in the modmail of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) Mute(redditor, note string) error {
+	return c.relationshipAction(true, RelMuted, redditor, map[string]string{"note": note})
+}
+
+// Unmute unmutes a redditor in the modmail of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) Unmute(redditor string) error {
+	return c.relationshipAction(false, RelMuted, redditor, nil)
+}
+
+// AddContributor makes redditor an approved user of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) AddContributor(redditor string) error {
+	return c.relationshipAction(true, RelContributor, redditor, nil)
+}
+
+// RemoveContributor removes redditor from the approved users of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) RemoveContributor(redditor string) error {
+	return c.relationshipAction(false, RelContributor, redditor, nil)
+}
+
+// WikiBan bans a redditor from the wiki of the last queued object. days = 0 bans permanently.
+// Valid objects: Subreddit
+func (c *Reddit) WikiBan(redditor string, days int, note string) error {
+	params := map[string]string{"note": note}
+	if days != 0 {
+		params["duration"] = strconv.Itoa(days)
+	}
+	return c.relationshipAction(true, RelWikiBanned, redditor, params)
+}
+
+// WikiUnban unbans a redditor from the wiki of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) WikiUnban(redditor string) error {
+	return c.relationshipAction(false, RelWikiBanned, redditor, nil)
+}
+
+// AddWikiContributor allows redditor to edit the wiki of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) AddWikiContributor(redditor string) error {
+	return c.relationshipAction(true, RelWikiContributor, redditor, nil)
+}
+
+// RemoveWikiContributor removes redditor from the wiki contributors of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) RemoveWikiContributor(redditor string) error {
+	return c.relationshipAction(false, RelWikiContributor, redditor, nil)
+}
+
+// BannedUsers returns a paginator for the banned users of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) BannedUsers() (*RelationshipPaginator, error) {
+	return c.relationships(RelBanned)
+}
+
+// MutedUsers returns a paginator for the muted users of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) MutedUsers() (*RelationshipPaginator, error) {
+	return c.relationships(RelMuted)
+}
+
+// Contributors returns a paginator for the approved users of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) Contributors() (*RelationshipPaginator, error) {
+	return c.relationships(RelContributor)
+}
+
+// WikiBannedUsers returns a paginator for the users banned from the wiki of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) WikiBannedUsers() (*RelationshipPaginator, error) {
+	return c.relationships(RelWikiBanned)
+}
+
+// WikiContributors returns a paginator for the wiki contributors of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) WikiContributors() (*RelationshipPaginator, error) {
+	return c.relationships(RelWikiContributor)
+}
+
+// RelationshipPaginator walks through a user list of a subreddit.
+type RelationshipPaginator struct {
+	c      *Reddit
+	target string
+	params map[string]string
+	done   bool
+}
+
+func (c *Reddit) relationships(rtype RelationshipType) (*RelationshipPaginator, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	listing, ok := relationshipListings[rtype]
+	if !ok {
+		return nil, fmt.Errorf("'%s' relationships can not be listed", rtype)
+	}
+	return &RelationshipPaginator{
+		c:      c,
+		target: RedditOauth + "/r/" + name + "/about/" + listing,
+		params: map[string]string{"limit": "100"},
+	}, nil
+}
+
+// Done tells you if the end of the list has been reached.
+func (p *RelationshipPaginator) Done() bool { return p.done }
+
+// Next returns the next page of the list.
+func (p *RelationshipPaginator) Next() ([]*models.Relationship, error) {
+	if p.done {
+		return []*models.Relationship{}, nil
+	}
+	ans, err := p.c.MiraRequest("GET", p.target, p.params)
+	if err != nil {
+		return nil, err
+	}
+	ret := &struct {
+		Data struct {
+			Children []*models.Relationship `json:"children"`
+			After    string                 `json:"after"`
+		} `json:"data"`
+	}{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	p.params["after"] = ret.Data.After
+	p.done = ret.Data.After == ""
+	return ret.Data.Children, nil
+}
+
+// Find returns the relationship of a single user, or nil if the user isn't on the list.
+func (p *RelationshipPaginator) Find(redditor string) (*models.Relationship, error) {
+	params := map[string]string{"user": redditor}
+	ans, err := p.c.MiraRequest("GET", p.target, params)
+	if err != nil {
+		return nil, err
+	}
+	ret := &struct {
+		Data struct {
+			Children []*models.Relationship `json:"children"`
+		} `json:"data"`
+	}{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	if len(ret.Data.Children) < 1 {
+		return nil, nil
+	}
+	return ret.Data.Children[0], nil
+}
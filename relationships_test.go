@@ -0,0 +1,140 @@
+package mira
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestRelationshipActions(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(c *Reddit) error
+		path string
+		want url.Values
+	}{
+		{
+			name: "ban",
+			call: func(c *Reddit) error { return c.Subreddit("test").Ban("bob", 3, "t3_p", "bye", "spam") },
+			path: "/r/test/api/friend",
+			want: url.Values{"name": {"bob"}, "type": {"banned"}, "duration": {"3"}, "ban_context": {"t3_p"},
+				"ban_message": {"bye"}, "ban_reason": {"spam"}, "note": {"spam"}},
+		},
+		{
+			name: "permanent ban",
+			call: func(c *Reddit) error { return c.Subreddit("test").EditBan("bob", 0, "bye", "spam") },
+			path: "/r/test/api/friend",
+			want: url.Values{"name": {"bob"}, "type": {"banned"}, "ban_context": {""},
+				"ban_message": {"bye"}, "ban_reason": {"spam"}, "note": {"spam"}},
+		},
+		{
+			name: "unban",
+			call: func(c *Reddit) error { return c.Subreddit("test").Unban("bob") },
+			path: "/r/test/api/unfriend",
+			want: url.Values{"name": {"bob"}, "type": {"banned"}},
+		},
+		{
+			name: "mute",
+			call: func(c *Reddit) error { return c.Subreddit("test").Mute("bob", "rude") },
+			path: "/r/test/api/friend",
+			want: url.Values{"name": {"bob"}, "type": {"muted"}, "note": {"rude"}},
+		},
+		{
+			name: "contributor",
+			call: func(c *Reddit) error { return c.Subreddit("test").AddContributor("bob") },
+			path: "/r/test/api/friend",
+			want: url.Values{"name": {"bob"}, "type": {"contributor"}},
+		},
+		{
+			name: "wiki ban",
+			call: func(c *Reddit) error { return c.Subreddit("test").WikiBan("bob", 7, "vandalism") },
+			path: "/r/test/api/friend",
+			want: url.Values{"name": {"bob"}, "type": {"wikibanned"}, "duration": {"7"}, "note": {"vandalism"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got url.Values
+			c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.path {
+					t.Errorf("request to %s, want %s", r.URL.Path, tt.path)
+				}
+				if err := r.ParseForm(); err != nil {
+					t.Error(err)
+				}
+				got = r.PostForm
+				fmt.Fprint(w, `{"json":{"errors":[]}}`)
+			}))
+			if err := tt.call(c); err != nil {
+				t.Fatal(err)
+			}
+			tt.want.Set("api_type", "json")
+			if got.Encode() != tt.want.Encode() {
+				t.Errorf("sent %s, want %s", got.Encode(), tt.want.Encode())
+			}
+		})
+	}
+}
+
+func TestRelationshipActionError(t *testing.T) {
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"json":{"errors":[["USER_DOESNT_EXIST","that user doesn't exist","name"]]}}`)
+	}))
+	err := c.Subreddit("test").Ban("nobody", 0, "", "", "")
+	if err == nil || err.Error() != "USER_DOESNT_EXIST: that user doesn't exist: name" {
+		t.Errorf("got %v, want reddit's error", err)
+	}
+}
+
+func TestRelationshipPaginator(t *testing.T) {
+	var queries []string
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/r/test/about/banned" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		queries = append(queries, q.Encode())
+		switch {
+		case q.Get("user") != "":
+			fmt.Fprint(w, `{"kind":"UserList","data":{"children":[]}}`)
+		case q.Get("after") == "":
+			fmt.Fprint(w, `{"kind":"UserList","data":{"children":[
+				{"rel_id":"rb_2","id":"t2_b","name":"bob","note":"spam","days_left":3},
+				{"rel_id":"rb_1","id":"t2_a","name":"alice","note":"rude","days_left":null}],"after":"rb_1"}}`)
+		default:
+			fmt.Fprint(w, `{"kind":"UserList","data":{"children":[{"rel_id":"rb_0","id":"t2_c","name":"carol"}],"after":null}}`)
+		}
+	}))
+
+	p, err := c.Subreddit("test").BannedUsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := p.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 2 || first[0].Name != "bob" || first[0].DaysLeft == nil || *first[0].DaysLeft != 3 || first[1].DaysLeft != nil {
+		t.Errorf("got first page %+v", first)
+	}
+	if p.Done() {
+		t.Fatal("done after the first page")
+	}
+	second, err := p.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second) != 1 || second[0].ID != "rb_0" || !p.Done() {
+		t.Errorf("got second page %+v, done %t", second, p.Done())
+	}
+	found, err := p.Find("nobody")
+	if err != nil || found != nil {
+		t.Errorf("Find returned %+v, %v", found, err)
+	}
+
+	want := []string{"limit=100", "after=rb_1&limit=100", "user=nobody"}
+	if fmt.Sprint(queries) != fmt.Sprint(want) {
+		t.Errorf("got queries %q, want %q", queries, want)
+	}
+}
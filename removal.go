@@ -0,0 +1,404 @@
+package mira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// RemovalDelivery defines how a removal reason is sent to the author.
+type RemovalDelivery string
+
+// List of all ways to deliver a removal reason
+const (
+	// RemovalNoMessage doesn't tell the author anything.
+	RemovalNoMessage RemovalDelivery = ""
+	// RemovalPublic replies with a distinguished & locked comment, which is stickied on posts.
+	RemovalPublic RemovalDelivery = "public"
+	// RemovalPrivate sends a private message from the subreddit.
+	RemovalPrivate RemovalDelivery = "private"
+	// RemovalModMail starts a modmail conversation with the author.
+	RemovalModMail RemovalDelivery = "modmail"
+)
+
+// RemovalOptions configures RemoveWithReason. All fields are optional.
+type RemovalOptions struct {
+	// Spam removes the item as spam.
+	Spam bool
+	// Reason is the message sent to the author, as a text/template executed with RemovalData.
+	Reason string
+	// Title is the subject of private messages & modmails, also a template. Defaults to
+	// "Your {{.Kind}} in r/{{.Subreddit}} was removed".
+	Title string
+	// Delivery defines how Reason is sent. Without a Reason, nothing is sent.
+	Delivery RemovalDelivery
+	// ReasonID is the ID of one of the subreddits native removal reasons.
+	ReasonID string
+	// ReasonTitle looks up a native removal reason by its title & sets ReasonID. If Reason is
+	// empty, the message of the removal reason is sent as is.
+	ReasonTitle string
+	// ModNote is a note only visible to moderators, stored with the removal.
+	ModNote string
+	// Lock locks the removed item.
+	Lock bool
+	// FlairText sets the flair of removed posts.
+	FlairText string
+}
+
+// RemovalData is passed to the Reason & Title templates of RemovalOptions.
+type RemovalData struct {
+	// Kind is either "post" or "comment".
+	Kind       string
+	Author     string
+	Subreddit  string
+	Title      string
+	URL        string
+	Submission models.Submission
+}
+
+// RemovalError is returned by RemoveWithReason if a step fails.
+type RemovalError struct {
+	// Step is the step that failed.
+	Step string
+	// Done are the steps that were completed before.
+	Done []string
+	// ReplyID is the ID of the removal comment if it has been posted (public delivery only).
+	ReplyID models.RedditID
+	Err     error
+}
+
+func (e *RemovalError) Error() string {
+	msg := "removal failed at step " + e.Step
+	if len(e.Done) > 0 {
+		msg += " (done: " + strings.Join(e.Done, ", ") + ")"
+	}
+	if e.ReplyID != "" {
+		msg += " (removal comment: " + string(e.ReplyID) + ")"
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *RemovalError) Unwrap() error { return e.Err }
+
+// RemoveWithReason removes the last queued object & tells the author why.
+//
+// It runs these steps in order, skipping the ones not needed by opts:
+//
+//	remove        removes the item
+//	reason        stores ReasonID & ModNote via reddits native removal reasons
+//	message       sends Reason to the author as defined by Delivery
+//	distinguish   distinguishes, stickies & locks the removal comment (public delivery only)
+//	lock          locks the item
+//	flair         sets the flair of the post
+//
+// If a step fails, the following steps are not run & a *RemovalError is returned.
+// Valid objects: Comment, Post
+func (c *Reddit) RemoveWithReason(opts RemovalOptions) error {
+	name, _, err := c.checkType(models.KComment, models.KPost)
+	if err != nil {
+		return err
+	}
+	return c.removeWithReason(models.RedditID(name), opts)
+}
+
+func (c *Reddit) removeWithReason(id models.RedditID, opts RemovalOptions) error {
+	// Render the message before changing anything, so a broken template doesn't leave a half done removal.
+	var title, reason string
+	var data *RemovalData
+	switch opts.Delivery {
+	case RemovalNoMessage, RemovalPublic, RemovalPrivate, RemovalModMail:
+	default:
+		return &RemovalError{Step: "prepare", Err: fmt.Errorf("'%s' is not a valid removal delivery", opts.Delivery)}
+	}
+	if opts.ReasonTitle != "" || (opts.Reason != "" && opts.Delivery != RemovalNoMessage) {
+		thing, err := c.SubmissionInfoID(id)
+		if err != nil {
+			return &RemovalError{Step: "prepare", Err: err}
+		}
+		data = &RemovalData{
+			Kind:       "comment",
+			Author:     thing.GetAuthor(),
+			Subreddit:  thing.GetSubreddit(),
+			Title:      thing.GetTitle(),
+			URL:        thing.GetURL(),
+			Submission: thing,
+		}
+		if id.Type() == models.KPost {
+			data.Kind = "post"
+		}
+	}
+	if opts.ReasonTitle != "" {
+		reasons, err := c.getRemovalReasons(data.Subreddit)
+		if err != nil {
+			return &RemovalError{Step: "prepare", Err: err}
+		}
+		r := reasons.ByTitle(opts.ReasonTitle)
+		if r == nil {
+			return &RemovalError{Step: "prepare", Err: fmt.Errorf("r/%s has no removal reason '%s'", data.Subreddit, opts.ReasonTitle)}
+		}
+		opts.ReasonID = r.ID
+		if opts.Reason == "" {
+			// Native removal reasons are plain text, they are not run as template.
+			reason = r.Message
+		}
+	}
+	if (opts.Reason != "" || reason != "") && opts.Delivery != RemovalNoMessage {
+		if opts.Title == "" {
+			opts.Title = "Your {{.Kind}} in r/{{.Subreddit}} was removed"
+		}
+		var err error
+		if title, err = executeRemovalTemplate(opts.Title, data); err != nil {
+			return &RemovalError{Step: "prepare", Err: err}
+		}
+		if opts.Reason != "" {
+			if reason, err = executeRemovalTemplate(opts.Reason, data); err != nil {
+				return &RemovalError{Step: "prepare", Err: err}
+			}
+		}
+	}
+
+	done := []string{}
+	var replyID models.RedditID
+	step := func(name string, f func() error) error {
+		if err := f(); err != nil {
+			return &RemovalError{Step: name, Done: done, ReplyID: replyID, Err: err}
+		}
+		done = append(done, name)
+		return nil
+	}
+
+	if err := step("remove", func() error {
+		return c.postAPI(RedditOauth+"/api/remove", map[string]string{
+			"id":   string(id),
+			"spam": strconv.FormatBool(opts.Spam),
+		})
+	}); err != nil {
+		return err
+	}
+
+	if opts.ReasonID != "" || opts.ModNote != "" {
+		if err := step("reason", func() error {
+			return c.addRemovalReason(id, opts.ReasonID, opts.ModNote)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if reason != "" && opts.Delivery != RemovalNoMessage {
+		if err := step("message", func() (err error) {
+			replyID, err = c.sendRemovalMessage(id, opts.Delivery, data, title, reason)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	if replyID != "" {
+		if err := step("distinguish", func() error {
+			return c.distinguishRemovalReply(id, replyID)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if opts.Lock {
+		if err := step("lock", func() error {
+			return c.postAPI(RedditOauth+"/api/lock", map[string]string{
+				"id": string(id),
+			})
+		}); err != nil {
+			return err
+		}
+	}
+
+	if opts.FlairText != "" && id.Type() == models.KPost {
+		if err := step("flair", func() error {
+			return c.postAPI(RedditOauth+"/api/selectflair", map[string]string{
+				"link": string(id),
+				"text": opts.FlairText,
+			})
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func executeRemovalTemplate(text string, data *RemovalData) (string, error) {
+	t, err := template.New("removal").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	buf := new(strings.Builder)
+	if err := t.Execute(buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// addRemovalReason stores a native removal reason and/or mod note for a removed item.
+func (c *Reddit) addRemovalReason(id models.RedditID, reasonID, modNote string) error {
+	payload := map[string]interface{}{
+		"item_ids": []string{string(id)},
+		"mod_note": modNote,
+	}
+	if reasonID != "" {
+		payload["reason_id"] = reasonID
+	}
+	_, err := c.miraRequestJSON("POST", RedditOauth+"/api/v1/modactions/removal_reasons", payload)
+	return err
+}
+
+// sendRemovalMessage sends reason to the author. For public delivery, it returns the ID of the removal comment.
+func (c *Reddit) sendRemovalMessage(id models.RedditID, delivery RemovalDelivery, data *RemovalData, title, reason string) (models.RedditID, error) {
+	switch delivery {
+	case RemovalPublic:
+		reply, err := c.ReplyWithID(string(id), reason)
+		if err != nil {
+			return "", err
+		}
+		if len(reply.JSON.Data.Things) < 1 {
+			return "", errors.New("reddit didn't return the removal comment")
+		}
+		return reply.JSON.Data.Things[0].Data.GetID(), nil
+	case RemovalPrivate:
+		return "", c.postAPI(RedditOauth+"/api/compose", map[string]string{
+			"subject": title,
+			"text":    reason,
+			"to":      data.Author,
+			"from_sr": data.Subreddit,
+		})
+	case RemovalModMail:
+		path := "/api/v1/modactions/removal_comment_message"
+		if id.Type() == models.KPost {
+			path = "/api/v1/modactions/removal_link_message"
+		}
+		_, err := c.miraRequestJSON("POST", RedditOauth+path, map[string]interface{}{
+			"item_id": []string{string(id)},
+			"title":   title,
+			"message": reason,
+			"type":    "private",
+		})
+		return "", err
+	}
+	return "", nil
+}
+
+// distinguishRemovalReply distinguishes & locks the removal comment replyID on the removed item id.
+func (c *Reddit) distinguishRemovalReply(id, replyID models.RedditID) error {
+	// Only top level comments can be stickied.
+	if err := c.postAPI(RedditOauth+"/api/distinguish", map[string]string{
+		"id":     string(replyID),
+		"how":    "yes",
+		"sticky": strconv.FormatBool(id.Type() == models.KPost),
+	}); err != nil {
+		return err
+	}
+	return c.postAPI(RedditOauth+"/api/lock", map[string]string{
+		"id": string(replyID),
+	})
+}
+
+func (c *Reddit) getRemovalReasons(sr string) (models.RemovalReasons, error) {
+	target := RedditOauth + "/api/v1/" + sr + "/removal_reasons"
+	ans, err := c.MiraRequest("GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	list := &struct {
+		Data  map[string]*models.RemovalReason `json:"data"`
+		Order []string                         `json:"order"`
+	}{}
+	if err := json.Unmarshal(ans, list); err != nil {
+		return nil, err
+	}
+	ret := models.RemovalReasons{}
+	for _, id := range list.Order {
+		if reason, ok := list.Data[id]; ok {
+			ret = append(ret, reason)
+		}
+	}
+	return ret, nil
+}
+
+// RemovalReasons returns the native removal reasons of the last queued object, in the order they are shown.
+// Valid objects: Subreddit
+func (c *Reddit) RemovalReasons() (models.RemovalReasons, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	return c.getRemovalReasons(name)
+}
+
+// AddRemovalReason adds a new removal reason to the last queued object & returns its ID.
+// Valid objects: Subreddit
+func (c *Reddit) AddRemovalReason(title, message string) (string, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return "", err
+	}
+	target := RedditOauth + "/api/v1/" + name + "/removal_reasons"
+	ans, err := c.MiraRequest("POST", target, map[string]string{
+		"title":   title,
+		"message": message,
+	})
+	if err != nil {
+		return "", err
+	}
+	ret := &struct {
+		ID string `json:"id"`
+	}{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return "", err
+	}
+	return ret.ID, nil
+}
+
+// UpdateRemovalReason changes the title & message of a removal reason of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) UpdateRemovalReason(id, title, message string) error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	target := RedditOauth + "/api/v1/" + name + "/removal_reasons/" + id
+	_, err = c.MiraRequest("PUT", target, map[string]string{
+		"title":   title,
+		"message": message,
+	})
+	return err
+}
+
+// DeleteRemovalReason deletes a removal reason of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) DeleteRemovalReason(id string) error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	target := RedditOauth + "/api/v1/" + name + "/removal_reasons/" + id
+	_, err = c.MiraRequest("DELETE", target, nil)
+	return err
+}
+
+// ReorderRemovalReasons sets the order of the removal reasons of the last queued object.
+// ids has to contain the IDs of all removal reasons.
+// Valid objects: Subreddit
+func (c *Reddit) ReorderRemovalReasons(ids []string) error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	target := RedditOauth + "/api/v1/" + name + "/removal_reasons"
+	_, err = c.MiraRequest("PATCH", target, map[string]string{
+		"order": strings.Join(ids, ","),
+	})
+	return err
+}
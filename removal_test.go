@@ -0,0 +1,204 @@
+package mira
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// removalServer answers all requests of RemoveWithReason for the post t3_p. Paths in fail return a reddit error.
+func removalServer(t *testing.T, fail ...string) (*Reddit, func() []string) {
+	var mu sync.Mutex
+	calls := []string{}
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		calls = append(calls, r.URL.Path+" "+r.PostForm.Get("id")+r.PostForm.Get("thing_id")+r.PostForm.Get("link"))
+		mu.Unlock()
+		for _, f := range fail {
+			if r.URL.Path == f {
+				fmt.Fprint(w, `{"json":{"errors":[["NO_PERMISSION","not allowed","id"]]}}`)
+				return
+			}
+		}
+		switch r.URL.Path {
+		case "/api/info.json":
+			fmt.Fprint(w, `{"kind":"Listing","data":{"children":[{"kind":"t3","data":{"name":"t3_p","author":"bob","subreddit":"test","title":"Hi"}}]}}`)
+		case "/api/comment":
+			if got := r.PostForm.Get("text"); got != "Removed your post in r/test, bob" {
+				t.Errorf("removal comment = %q", got)
+			}
+			fmt.Fprint(w, `{"json":{"errors":[],"data":{"things":[{"kind":"t1","data":{"name":"t1_reply"}}]}}}`)
+		default:
+			fmt.Fprint(w, `{"json":{"errors":[]}}`)
+		}
+	}))
+	return c, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, calls...)
+	}
+}
+
+func TestRemoveWithReasonPublic(t *testing.T) {
+	c, calls := removalServer(t)
+	err := c.Post("t3_p").RemoveWithReason(RemovalOptions{
+		Reason:    "Removed your {{.Kind}} in r/{{.Subreddit}}, {{.Author}}",
+		Delivery:  RemovalPublic,
+		Lock:      true,
+		FlairText: "Removed",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"/api/info.json ",
+		"/api/remove t3_p",
+		"/api/comment t3_p",
+		"/api/distinguish t1_reply",
+		"/api/lock t1_reply",
+		"/api/lock t3_p",
+		"/api/selectflair t3_p",
+	}
+	if got := calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got requests %q, want %q", got, want)
+	}
+}
+
+func TestRemoveWithReasonError(t *testing.T) {
+	opts := RemovalOptions{Reason: "Removed your {{.Kind}} in r/{{.Subreddit}}, {{.Author}}", Delivery: RemovalPublic, Lock: true}
+	tests := []struct {
+		fail    string
+		step    string
+		done    []string
+		replyID models.RedditID
+		msg     string
+	}{
+		{
+			fail: "/api/remove",
+			step: "remove",
+			msg:  "removal failed at step remove: NO_PERMISSION: not allowed: id",
+		},
+		{
+			fail: "/api/comment",
+			step: "message",
+			done: []string{"remove"},
+			msg:  "removal failed at step message (done: remove): NO_PERMISSION: not allowed: id",
+		},
+		{
+			// The removal comment has been posted, so it must not be posted again on retry.
+			fail:    "/api/distinguish",
+			step:    "distinguish",
+			done:    []string{"remove", "message"},
+			replyID: "t1_reply",
+			msg:     "removal failed at step distinguish (done: remove, message) (removal comment: t1_reply): NO_PERMISSION: not allowed: id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.step, func(t *testing.T) {
+			c, _ := removalServer(t, tt.fail)
+			err := c.Post("t3_p").RemoveWithReason(opts)
+			var rerr *RemovalError
+			if !errors.As(err, &rerr) {
+				t.Fatalf("got %v, want a *RemovalError", err)
+			}
+			if rerr.Step != tt.step || strings.Join(rerr.Done, ",") != strings.Join(tt.done, ",") || rerr.ReplyID != tt.replyID {
+				t.Errorf("got step %s, done %q, reply %s", rerr.Step, rerr.Done, rerr.ReplyID)
+			}
+			if err.Error() != tt.msg {
+				t.Errorf("got message %q, want %q", err, tt.msg)
+			}
+			var apiErr models.APIErrors
+			if !errors.As(err, &apiErr) {
+				t.Errorf("%v doesn't wrap the reddit error", err)
+			}
+		})
+	}
+}
+
+func TestRemovalReasons(t *testing.T) {
+	type request struct {
+		method, path string
+		form         string
+	}
+	var got []request
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		got = append(got, request{r.Method, r.URL.Path, r.PostForm.Encode()})
+		switch {
+		case r.Method == "GET":
+			fmt.Fprint(w, `{"data":{"r1":{"id":"r1","title":"Spam","message":"No spam"},"r2":{"id":"r2","title":"Off topic","message":"Stay on topic"}},"order":["r2","r1"]}`)
+		case r.Method == "POST":
+			fmt.Fprint(w, `{"id":"r3"}`)
+		default:
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+
+	reasons, err := c.Subreddit("test").RemovalReasons()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reasons) != 2 || reasons[0].ID != "r2" || reasons[1].ID != "r1" {
+		t.Errorf("got reasons %+v, want r2 & r1", reasons)
+	}
+	if r := reasons.ByTitle("spam"); r == nil || r.ID != "r1" {
+		t.Errorf("ByTitle returned %+v, want r1", r)
+	}
+	id, err := c.Subreddit("test").AddRemovalReason("Rude", "Be nice")
+	if err != nil || id != "r3" {
+		t.Errorf("AddRemovalReason returned %s, %v", id, err)
+	}
+	if err := c.Subreddit("test").UpdateRemovalReason("r3", "Rude", "Be nicer"); err != nil {
+		t.Error(err)
+	}
+	if err := c.Subreddit("test").ReorderRemovalReasons([]string{"r1", "r2", "r3"}); err != nil {
+		t.Error(err)
+	}
+	if err := c.Subreddit("test").DeleteRemovalReason("r3"); err != nil {
+		t.Error(err)
+	}
+
+	want := []request{
+		{"GET", "/api/v1/test/removal_reasons", ""},
+		{"POST", "/api/v1/test/removal_reasons", "message=Be+nice&title=Rude"},
+		{"PUT", "/api/v1/test/removal_reasons/r3", "message=Be+nicer&title=Rude"},
+		{"PATCH", "/api/v1/test/removal_reasons", "order=r1%2Cr2%2Cr3"},
+		{"DELETE", "/api/v1/test/removal_reasons/r3", ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got requests %+v, want %+v", got, want)
+	}
+}
+
+func TestRemovalReasonsError(t *testing.T) {
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"Forbidden","error":"403"}`)
+	}))
+
+	const want = "Forbidden | error code: 403"
+	check := func(name string, err error) {
+		t.Helper()
+		if err == nil || err.Error() != want {
+			t.Errorf("%s returned %v, want %s", name, err, want)
+		}
+	}
+	_, err := c.Subreddit("test").RemovalReasons()
+	check("RemovalReasons", err)
+	_, err = c.Subreddit("test").AddRemovalReason("Rude", "Be nice")
+	check("AddRemovalReason", err)
+	check("UpdateRemovalReason", c.Subreddit("test").UpdateRemovalReason("r3", "Rude", "Be nicer"))
+	check("ReorderRemovalReasons", c.Subreddit("test").ReorderRemovalReasons([]string{"r1"}))
+	check("DeleteRemovalReason", c.Subreddit("test").DeleteRemovalReason("r3"))
+}
@@ -0,0 +1,85 @@
+package mira
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// Rules returns the rules of the last queued object, in the order they are shown.
+// Valid objects: Subreddit
+func (c *Reddit) Rules() (models.Rules, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	target := RedditOauth + "/r/" + name + "/about/rules"
+	ans, err := c.MiraRequest("GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	ret := &struct {
+		Rules models.Rules `json:"rules"`
+	}{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(ret.Rules, func(i, j int) bool { return ret.Rules[i].Priority < ret.Rules[j].Priority })
+	return ret.Rules, nil
+}
+
+// AddRule adds a rule to the last queued object. Only Kind, ShortName, Description & ViolationReason are used.
+// Valid objects: Subreddit
+func (c *Reddit) AddRule(rule models.Rule) error {
+	return c.ruleRequest("/api/add_subreddit_rule", ruleParams(rule))
+}
+
+// UpdateRule changes the rule with the short name oldShortName of the last queued object.
+// Only Kind, ShortName, Description & ViolationReason are used.
+// Valid objects: Subreddit
+func (c *Reddit) UpdateRule(oldShortName string, rule models.Rule) error {
+	params := ruleParams(rule)
+	params["old_short_name"] = oldShortName
+	return c.ruleRequest("/api/update_subreddit_rule", params)
+}
+
+// DeleteRule deletes a rule of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) DeleteRule(shortName string) error {
+	return c.ruleRequest("/api/remove_subreddit_rule", map[string]string{
+		"short_name": shortName,
+	})
+}
+
+// ReorderRules sets the order of the rules of the last queued object.
+// shortNames has to contain the short names of all rules.
+// Valid objects: Subreddit
+func (c *Reddit) ReorderRules(shortNames []string) error {
+	return c.ruleRequest("/api/reorder_subreddit_rules", map[string]string{
+		"new_rule_order": strings.Join(shortNames, ","),
+	})
+}
+
+func ruleParams(rule models.Rule) map[string]string {
+	kind := rule.Kind
+	if kind == "" {
+		kind = "all"
+	}
+	return map[string]string{
+		"kind":             kind,
+		"short_name":       rule.ShortName,
+		"description":      rule.Description,
+		"violation_reason": rule.ViolationReason,
+	}
+}
+
+func (c *Reddit) ruleRequest(path string, params map[string]string) error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	params["r"] = name
+	return c.postAPI(RedditOauth+path, params)
+}
@@ -2,29 +2,45 @@ package mira
 
 import (
 	"container/ring"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/ttgmpsn/mira/models"
 )
 
+// errStreamClosed is used internally to stop a stream once its Close channel has been closed.
+var errStreamClosed = errors.New("stream has been closed")
+
 // SubmissionStream has two objects: a channel "C" where you can receive
 // Submissions (posts or comments), and a channel "close" - close that
 // channel to stop receiving events. Please use the close channel appropriately
-// or you'll be polling reddit non-stop!
+// or you'll be polling reddit non-stop! C is closed once the stream has stopped, either
+// because Close was closed or because of an error.
+//
+// By default, the stream polls at a fixed interval. Use Interval to change the bounds for a single
+// stream & let the time between two polls adapt to how busy the stream is, see StreamInterval.
 type SubmissionStream struct {
-	C     <-chan models.Submission
-	Close chan struct{}
+	C        <-chan models.Submission
+	Close    chan struct{}
+	Interval *StreamInterval
 }
 
 // StreamComments streams comments for the last queued object.
+// For posts, every new comment in the thread is sent once, no matter how deeply nested it is (see StreamPostComments).
 // The fetch interval can be set via reddit.Config.CommentStreamInterval
-// Valid objects: Subreddit, (Redditor)
+// If reddit.Config.Checkpointer is set, the stream resumes where it left off before a restart.
+// Valid objects: Subreddit, Post, (Redditor)
 func (c *Reddit) StreamComments() (*SubmissionStream, error) {
 	name, ttype := c.getQueue()
 	switch ttype {
 	case models.KSubreddit:
 		return c.streamSubredditComments(name)
+	case models.KPost:
+		return c.streamPostComments(models.RedditID(name), false)
 	/*case models.KRedditor:
 	return c.streamRedditorComments(name)*/
 	default:
@@ -32,8 +48,23 @@ func (c *Reddit) StreamComments() (*SubmissionStream, error) {
 	}
 }
 
+// StreamPostComments streams every new comment in the thread of the last queued object, no matter how
+// deeply nested it is. Like the other streams, it only sends comments made after the stream has been started.
+// If backfill is set, all comments already in the thread are sent first (oldest first).
+// The fetch interval can be set via reddit.Config.CommentStreamInterval
+// If reddit.Config.Checkpointer is set, the stream resumes where it left off before a restart.
+// Valid objects: Post
+func (c *Reddit) StreamPostComments(backfill bool) (*SubmissionStream, error) {
+	name, _, err := c.checkType(models.KPost)
+	if err != nil {
+		return nil, err
+	}
+	return c.streamPostComments(models.RedditID(name), backfill)
+}
+
 // StreamPosts streams posts for the last queued object.
 // The fetch interval can be set via reddit.Config.PostStreamInterval
+// If reddit.Config.Checkpointer is set, the stream resumes where it left off before a restart.
 // Valid objects: Subreddit, (Redditor)
 func (c *Reddit) StreamPosts() (*SubmissionStream, error) {
 	name, ttype := c.getQueue()
@@ -47,92 +78,436 @@ func (c *Reddit) StreamPosts() (*SubmissionStream, error) {
 	}
 }
 
+// StreamModQueue streams new items in the mod queue of the last queued object.
+// The fetch interval can be set via reddit.Config.PostStreamInterval
+// If reddit.Config.Checkpointer is set, the stream resumes where it left off before a restart.
+// Valid objects: Subreddit
+func (c *Reddit) StreamModQueue() (*SubmissionStream, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	target := RedditOauth + "/r/" + name + "/about/modqueue.json"
+	if _, err := c.miraRequestListing("GET", target, map[string]string{"limit": "1"}); err != nil {
+		return nil, err
+	}
+	return c.streamListing("modqueue:"+name, target, map[string]string{}, c.Config.PostStreamInterval)
+}
+
+// StreamMentions streams username mentions from the inbox of the last queued object.
+// The fetch interval can be set via reddit.Config.CommentStreamInterval
+// If reddit.Config.Checkpointer is set, the stream resumes where it left off before a restart.
+// Valid objects: Me
+func (c *Reddit) StreamMentions() (*SubmissionStream, error) {
+	_, ttype := c.getQueue()
+	if ttype != "me" {
+		return nil, fmt.Errorf("'%s' type does not have an option to stream mentions", ttype)
+	}
+	target := RedditOauth + "/message/mentions"
+	return c.streamListing("mentions", target, map[string]string{"mark": "false"}, c.Config.CommentStreamInterval)
+}
+
 func (c *Reddit) streamSubredditComments(name string) (*SubmissionStream, error) {
+	if _, err := c.getSubredditPosts(name, "new", "all", 1); err != nil {
+		return nil, err
+	}
+	target := RedditOauth + "/r/" + name + "/comments.json"
+	return c.streamListing("comments:"+name, target, map[string]string{"sort": "new"}, c.Config.CommentStreamInterval)
+}
+
+func (c *Reddit) streamSubredditPosts(name string) (*SubmissionStream, error) {
+	if _, err := c.getSubredditPosts(name, "new", "all", 1); err != nil {
+		return nil, err
+	}
+	target := RedditOauth + "/r/" + name + "/new.json"
+	return c.streamListing("posts:"+name, target, map[string]string{}, c.Config.PostStreamInterval)
+}
+
+// threadMoreRequests is the number of requests a post comment stream makes per poll to load
+// comments hidden behind "load more comments" & "continue this thread" links.
+const threadMoreRequests = 10
+
+func (c *Reddit) streamPostComments(postID models.RedditID, backfill bool) (*SubmissionStream, error) {
+	if _, err := c.getPost(postID); err != nil {
+		return nil, err
+	}
+	key := "thread:" + string(postID)
+	var checkpoint uint64
+	if c.Config.Checkpointer != nil {
+		cp, err := c.Config.Checkpointer.Load(key)
+		if err != nil {
+			return nil, err
+		}
+		if cp != "" {
+			checkpoint = models.RedditID(cp).Number()
+			backfill = true
+		}
+	}
+
 	sendC := make(chan models.Submission, 100)
 	s := &SubmissionStream{
-		C:     sendC,
-		Close: make(chan struct{}),
+		C:        sendC,
+		Close:    make(chan struct{}),
+		Interval: newStreamInterval(c.Config.CommentStreamInterval, c.Config.StreamMinInterval, c.Config.StreamMaxInterval),
 	}
-	_, err := c.Subreddit(name).Posts("new", "all", 1)
+	go func() {
+		atomic.AddInt32(&c.streams, 1)
+		defer atomic.AddInt32(&c.streams, -1)
+		defer close(sendC)
+		seen := make(map[models.RedditID]bool)
+		first := true
+		for {
+			select {
+			case <-s.Close:
+				return
+			default:
+			}
+			comments, err := c.getNewThreadComments(postID, seen)
+			if err != nil {
+				return
+			}
+
+			sent := 0
+			for _, comment := range comments {
+				seen[comment.GetID()] = true
+				// Without a backfill, the first poll only remembers what is already there. When resuming, only
+				// what came after the checkpoint is sent. Afterwards, seen tells what is new.
+				if first && (!backfill || comment.GetID().Number() <= checkpoint) {
+					continue
+				}
+				select {
+				case sendC <- comment:
+				case <-s.Close:
+					return
+				}
+				sent++
+			}
+			if c.Config.Checkpointer != nil && len(comments) > 0 {
+				newest := comments[len(comments)-1].GetID()
+				if newest.Number() > checkpoint {
+					if err := c.Config.Checkpointer.Save(key, string(newest)); err != nil {
+						return
+					}
+					checkpoint = newest.Number()
+				}
+			}
+			first = false
+			c.waitStream(s.Interval.next(sent, 100), s.Close)
+		}
+	}()
+	return s, nil
+}
+
+// getNewThreadComments loads a thread sorted by new & expands the hidden comments that are not in seen yet.
+// The comments not in seen are returned oldest first.
+func (c *Reddit) getNewThreadComments(postID models.RedditID, seen map[models.RedditID]bool) ([]*models.Comment, error) {
+	tree, err := c.getCommentTree(postID, map[string]string{
+		"sort":  "new",
+		"limit": "500",
+	})
 	if err != nil {
 		return nil, err
 	}
+
+	for i := 0; i < threadMoreRequests; i++ {
+		// Load stubs hiding unseen comments first. "Continue this thread" links don't tell
+		// which comments they hide, so they have to be loaded in any case.
+		var next *models.More
+		for _, m := range tree.MoreStubs() {
+			if m.IsContinueThread() {
+				if next == nil {
+					next = m
+				}
+				continue
+			}
+			unseen := []string{}
+			for _, id := range m.Children {
+				if !seen[models.RedditID("t1_"+id)] {
+					unseen = append(unseen, id)
+				}
+			}
+			m.Children = unseen
+			if len(m.Children) == 0 {
+				tree.RemoveMore(m)
+				continue
+			}
+			next = m
+			break
+		}
+		if next == nil {
+			break
+		}
+		if err := c.expandMore(tree, next, "new"); err != nil {
+			return nil, err
+		}
+	}
+
+	ret := []*models.Comment{}
+	for _, comment := range tree.Flatten() {
+		if !seen[comment.GetID()] {
+			ret = append(ret, comment)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].GetID().Number() < ret[j].GetID().Number() })
+	return ret, nil
+}
+
+// streamListing polls a listing sorted by new and sends each item once.
+// If reddit.Config.Checkpointer is set, the newest sent item is saved under key and the stream
+// starts by catching up on everything after the saved item.
+func (c *Reddit) streamListing(key, target string, params map[string]string, interval int) (*SubmissionStream, error) {
+	sendC := make(chan models.Submission, 100)
+	s := &SubmissionStream{
+		C:        sendC,
+		Close:    make(chan struct{}),
+		Interval: newStreamInterval(interval, c.Config.StreamMinInterval, c.Config.StreamMaxInterval),
+	}
 	var last models.RedditID
+	if c.Config.Checkpointer != nil {
+		cp, err := c.Config.Checkpointer.Load(key)
+		if err != nil {
+			return nil, err
+		}
+		last = models.RedditID(cp)
+	}
 	go func() {
+		atomic.AddInt32(&c.streams, 1)
+		defer atomic.AddInt32(&c.streams, -1)
+		defer close(sendC)
 		sent := ring.New(100)
+		// send sends all items not sent yet (oldest first), saves the newest one as checkpoint
+		// and returns the number of sent items.
+		send := func(items []models.RedditThing) (int, error) {
+			n := 0
+			var newest models.RedditID
+			for _, item := range items {
+				sub, ok := item.(models.Submission)
+				if !ok || ringContains(sent, sub.GetID()) {
+					continue
+				}
+				select {
+				case sendC <- sub:
+				case <-s.Close:
+					return n, errStreamClosed
+				}
+				sent.Value = sub.GetID()
+				sent = sent.Next()
+				newest = sub.GetID()
+				n++
+			}
+			if c.Config.Checkpointer == nil || newest == "" {
+				return n, nil
+			}
+			return n, c.Config.Checkpointer.Save(key, string(newest))
+		}
+
+		if last != "" {
+			p := c.Paginate(target, params, last, true)
+			for !p.Done() {
+				select {
+				case <-s.Close:
+					return
+				default:
+				}
+				items, err := p.Next()
+				if err == nil {
+					_, err = send(items)
+				}
+				if err != nil {
+					return
+				}
+				if len(items) > 0 {
+					last = items[len(items)-1].GetID()
+				}
+			}
+		}
+
+		live := map[string]string{"limit": "100"}
+		for k, v := range params {
+			live[k] = v
+		}
 		for {
 			select {
 			case <-s.Close:
 				return
 			default:
 			}
-			comments, err := c.Subreddit(name).CommentsAfter("new", last, 100)
+			live["before"] = string(last)
+			list, err := c.miraRequestListing("GET", target, live)
 			if err != nil {
-				close(sendC)
 				return
 			}
-			for i := len(comments) - 1; i >= 0; i-- {
-				if ringContains(sent, comments[i].GetID()) {
-					continue
-				}
-				sendC <- comments[i]
-				sent.Value = comments[i].GetID()
-				sent = sent.Next()
+			items := make([]models.RedditThing, len(list.Children))
+			for i, child := range list.Children {
+				items[len(items)-1-i] = child.Data
 			}
-			if len(comments) == 0 {
+			// The poll overlaps with the last one (see before above), so only count what is new.
+			n, err := send(items)
+			if err != nil {
+				return
+			}
+			if len(items) == 0 {
 				last = ""
-			} else if len(comments) > 2 {
-				last = comments[1].GetID()
+			} else if len(items) > 2 {
+				last = items[len(items)-2].GetID()
 			}
-			time.Sleep(time.Duration(c.Config.CommentStreamInterval) * time.Second)
+			c.waitStream(s.Interval.next(n, 100), s.Close)
 		}
 	}()
 	return s, nil
 }
 
-func (c *Reddit) streamSubredditPosts(name string) (*SubmissionStream, error) {
-	sendC := make(chan models.Submission, 100)
-	s := &SubmissionStream{
-		C:     sendC,
-		Close: make(chan struct{}),
+// ModMailEventType tells what happened in a modmail conversation.
+type ModMailEventType int
+
+// List of all events a ModMailStream can send
+const (
+	// ModMailNewConversation is sent when a conversation has been created. Message is its first message.
+	ModMailNewConversation ModMailEventType = iota
+	// ModMailNewMessage is sent for each new message in a conversation.
+	ModMailNewMessage
+)
+
+// ModMailEvent is a single change to a modmail conversation.
+type ModMailEvent struct {
+	Type         ModMailEventType
+	Conversation *models.NewModmailConversation
+	Message      *models.NewModmailMessage
+}
+
+// ModMailStream works like SubmissionStream, but sends ModMailEvents.
+type ModMailStream struct {
+	C        <-chan *ModMailEvent
+	Close    chan struct{}
+	Interval *StreamInterval
+}
+
+// StreamModMail streams new modmail conversations & messages for the last queued object.
+// Queue Me() to stream the modmail of all subreddits you moderate.
+// Only conversations in the given state are watched.
+// The fetch interval can be set via reddit.Config.ModMailStreamInterval
+// If reddit.Config.Checkpointer is set, the stream resumes where it left off before a restart.
+// Valid objects: Subreddit, Me
+func (c *Reddit) StreamModMail(state ModMailState) (*ModMailStream, error) {
+	name, ttype := c.getQueue()
+	switch ttype {
+	case models.KSubreddit:
+		return c.streamModMail(strings.ReplaceAll(name, "+", ","), state)
+	case "me":
+		return c.streamModMail("", state)
+	default:
+		return nil, fmt.Errorf("'%s' type does not have an option to stream modmail", ttype)
+	}
+}
+
+func (c *Reddit) streamModMail(entity string, state ModMailState) (*ModMailStream, error) {
+	sendC := make(chan *ModMailEvent, 100)
+	s := &ModMailStream{
+		C:        sendC,
+		Close:    make(chan struct{}),
+		Interval: newStreamInterval(c.Config.ModMailStreamInterval, c.Config.StreamMinInterval, c.Config.StreamMaxInterval),
 	}
-	_, err := c.Subreddit(name).Posts("new", "all", 1)
+	key := "modmail:" + entity + ":" + string(state)
+	since, err := c.modMailCheckpoint(key)
 	if err != nil {
 		return nil, err
 	}
-	var last models.RedditID
+	if since.IsZero() {
+		// Only changes after the stream has been started are sent.
+		latest, err := c.getModMailUpdates(entity, state, "", 1)
+		if err != nil {
+			return nil, err
+		}
+		for _, conv := range latest.Conversations {
+			if conv.LastUpdated != nil {
+				since = *conv.LastUpdated
+			}
+		}
+	}
 	go func() {
-		sent := ring.New(100)
+		atomic.AddInt32(&c.streams, 1)
+		defer atomic.AddInt32(&c.streams, -1)
+		defer close(sendC)
+		// Conversations are fetched after listing them, so they can contain messages newer than
+		// the listed update time. Those are sent right away & remembered until since passes them.
+		sent := make(map[string]time.Time)
 		for {
 			select {
 			case <-s.Close:
 				return
 			default:
 			}
-			posts, err := c.Subreddit(name).PostsAfter(last, 100)
+			ids, newest, err := c.getModMailUpdatedSince(entity, state, since)
 			if err != nil {
-				close(sendC)
 				return
 			}
-			for i := len(posts) - 1; i >= 0; i-- {
-				if ringContains(sent, posts[i].GetID()) {
-					continue
+			for _, id := range ids {
+				conv, err := c.GetModMailByID(id, false)
+				if err != nil {
+					return
+				}
+				for _, e := range modMailEventsSince(conv, since) {
+					if _, ok := sent[e.Message.ID]; ok {
+						continue
+					}
+					select {
+					case sendC <- e:
+					case <-s.Close:
+						return
+					}
+					sent[e.Message.ID] = e.Message.Date
 				}
-				sendC <- posts[i]
-				sent.Value = posts[i].GetID()
-				sent = sent.Next()
 			}
-			if len(posts) == 0 {
-				last = ""
-			} else if len(posts) > 2 {
-				last = posts[1].GetID()
+			since = newest
+			for id, date := range sent {
+				if !date.After(since) {
+					delete(sent, id)
+				}
 			}
-			time.Sleep(time.Duration(c.Config.PostStreamInterval) * time.Second)
+			if c.Config.Checkpointer != nil && len(ids) > 0 {
+				if err := c.Config.Checkpointer.Save(key, since.Format(time.RFC3339Nano)); err != nil {
+					return
+				}
+			}
+			// Conversations are paginated, so nothing is ever missed. Still poll faster while there is activity.
+			c.waitStream(s.Interval.next(len(ids), 1), s.Close)
 		}
 	}()
 	return s, nil
 }
 
+// modMailCheckpoint returns the saved position of a modmail stream, or the zero time if there is none.
+func (c *Reddit) modMailCheckpoint(key string) (time.Time, error) {
+	if c.Config.Checkpointer == nil {
+		return time.Time{}, nil
+	}
+	cp, err := c.Config.Checkpointer.Load(key)
+	if err != nil || cp == "" {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339Nano, cp)
+}
+
+// modMailEventsSince returns an event for each message in conv that was sent after since.
+func modMailEventsSince(conv *models.NewModmailConversation, since time.Time) []*ModMailEvent {
+	ret := []*ModMailEvent{}
+	for i, m := range conv.Messages {
+		if !m.Date.After(since) {
+			continue
+		}
+		e := &ModMailEvent{
+			Type:         ModMailNewMessage,
+			Conversation: conv,
+			Message:      m,
+		}
+		if i == 0 {
+			e.Type = ModMailNewConversation
+		}
+		ret = append(ret, e)
+	}
+	return ret
+}
+
 func ringContains(r *ring.Ring, n models.RedditID) bool {
 	ret := false
 	r.Do(func(p interface{}) {
@@ -0,0 +1,131 @@
+package mira
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// listingJSON returns a listing of comments with the given IDs, newest first.
+func listingJSON(ids ...string) string {
+	children := make([]string, len(ids))
+	for i, id := range ids {
+		children[i] = fmt.Sprintf(`{"kind":"t1","data":{"id":"%s","name":"t1_%s","subreddit":"test"}}`, id, id)
+	}
+	return `{"kind":"Listing","data":{"children":[` + strings.Join(children, ",") + `]}}`
+}
+
+func TestStreamListingIdlePollBacksOff(t *testing.T) {
+	var mu sync.Mutex
+	polls := 0
+	ready := make(chan struct{})
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-ready
+		mu.Lock()
+		polls++
+		mu.Unlock()
+		// Reddit keeps returning the already seen items when nothing new has been posted.
+		fmt.Fprint(w, listingJSON("c", "b", "a"))
+	}))
+
+	s, err := c.streamListing("test", RedditOauth+"/r/test/comments.json", map[string]string{}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(s.Close)
+	s.Interval.mu.Lock()
+	s.Interval.current, s.Interval.min, s.Interval.max = 10*time.Millisecond, 10*time.Millisecond, time.Second
+	s.Interval.mu.Unlock()
+	close(ready)
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-s.C:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the first poll")
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		n := polls
+		mu.Unlock()
+		if n >= 4 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for idle polls")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	select {
+	case s := <-s.C:
+		t.Fatalf("idle poll sent %s again", s.GetID())
+	default:
+	}
+	if got := s.Interval.Current(); got <= 10*time.Millisecond {
+		t.Errorf("interval didn't back off on idle polls: %s", got)
+	}
+}
+
+func TestStreamModMailSendsLateMessagesOnce(t *testing.T) {
+	var mu sync.Mutex
+	lists := 0
+	c := newTestReddit(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/mod/conversations":
+			lists++
+			// The first list starts the stream. m2 is sent after the second list, but before the conversation is fetched.
+			updated := "2020-01-01T00:00:00Z"
+			switch {
+			case lists == 2:
+				updated = "2020-01-02T00:00:00Z"
+			case lists > 2:
+				updated = "2020-01-03T00:00:00Z"
+			}
+			fmt.Fprintf(w, `{"conversationIds":["abc"],"conversations":{"abc":{"lastUpdated":"%s"}}}`, updated)
+		case "/api/mod/conversations/abc":
+			fmt.Fprint(w, `{"conversation":{"id":"abc"},"messages":[
+				{"id":"m0","date":"2020-01-01T00:00:00Z"},
+				{"id":"m1","date":"2020-01-02T00:00:00Z"},
+				{"id":"m2","date":"2020-01-03T00:00:00Z"}]}`)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	c.Config.ModMailStreamInterval = 1
+
+	s, err := c.Me().StreamModMail(ModMailAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	timeout := time.After(5 * time.Second)
+	for {
+		mu.Lock()
+		done := lists > 3
+		mu.Unlock()
+		if done {
+			break
+		}
+		select {
+		case e := <-s.C:
+			got = append(got, e.Message.ID)
+		case <-time.After(10 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("the stream didn't poll")
+		}
+	}
+	close(s.Close)
+	for e := range s.C {
+		got = append(got, e.Message.ID)
+	}
+	if strings.Join(got, ",") != "m1,m2" {
+		t.Errorf("got messages %v, want m1,m2", got)
+	}
+}
@@ -0,0 +1,176 @@
+package mira
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// Widgets returns all sidebar widgets of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) Widgets() (*models.Widgets, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	target := RedditOauth + "/r/" + name + "/api/widgets"
+	ans, err := c.MiraRequest("GET", target, map[string]string{
+		"progressive_images": "true",
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &models.Widgets{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// AddWidget adds w to the sidebar of the last queued object & returns the created widget.
+// Valid objects: Subreddit
+func (c *Reddit) AddWidget(w models.Widget) (models.Widget, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	return c.widgetRequest("POST", RedditOauth+"/r/"+name+"/api/widget", w)
+}
+
+// UpdateWidget replaces the widget with the ID of w in the last queued object & returns the updated widget.
+// Valid objects: Subreddit
+func (c *Reddit) UpdateWidget(w models.Widget) (models.Widget, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	if w.GetID() == "" {
+		return nil, fmt.Errorf("widget has no ID")
+	}
+	return c.widgetRequest("PUT", RedditOauth+"/r/"+name+"/api/widget/"+w.GetID(), w)
+}
+
+// DeleteWidget deletes a widget of the last queued object.
+// Valid objects: Subreddit
+func (c *Reddit) DeleteWidget(id string) error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	target := RedditOauth + "/r/" + name + "/api/widget/" + id
+	_, err = c.MiraRequest("DELETE", target, nil)
+	return err
+}
+
+// ReorderWidgets sets the order of the widgets in the sidebar of the last queued object.
+// ids has to contain the IDs of all sidebar widgets.
+// Valid objects: Subreddit
+func (c *Reddit) ReorderWidgets(ids []string) error {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	target := RedditOauth + "/r/" + name + "/api/widget_order/sidebar"
+	_, err = c.miraRequestJSON("PATCH", target, ids)
+	return err
+}
+
+// UploadWidgetImage uploads an image for use in ImageWidgets, CustomWidgets & image buttons
+// of the last queued object & returns its URL.
+// Valid objects: Subreddit
+func (c *Reddit) UploadWidgetImage(image Media) (string, error) {
+	name, _, err := c.checkType(models.KSubreddit)
+	if err != nil {
+		return "", err
+	}
+	filename, mimeType, r, done, err := image.open()
+	if err != nil {
+		return "", err
+	}
+	defer done()
+
+	target := RedditOauth + "/r/" + name + "/api/widget_image_upload_s3"
+	ans, err := c.MiraRequest("POST", target, map[string]string{
+		"filepath": filename,
+		"mimetype": mimeType,
+	})
+	if err != nil {
+		return "", err
+	}
+	lease := &struct {
+		S3UploadLease uploadLease `json:"s3UploadLease"`
+	}{}
+	if err := json.Unmarshal(ans, lease); err != nil {
+		return "", err
+	}
+	return c.upload(&lease.S3UploadLease, filename, r)
+}
+
+func (c *Reddit) widgetRequest(method, target string, w models.Widget) (models.Widget, error) {
+	payload, err := widgetPayload(w)
+	if err != nil {
+		return nil, err
+	}
+	ans, err := c.miraRequestJSON(method, target, payload)
+	if err != nil {
+		return nil, err
+	}
+	return models.UnmarshalWidget(ans)
+}
+
+// widgetPayload converts w into what reddit expects when writing a widget:
+// Without the ID & the fields generated by reddit, and community lists only containing the names.
+func widgetPayload(w models.Widget) (map[string]interface{}, error) {
+	if u, ok := w.(*models.UnknownWidget); ok {
+		return nil, fmt.Errorf("'%s' widgets can't be written", u.Kind)
+	}
+	data, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]interface{})
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, err
+	}
+	delete(ret, "id")
+	if ret["kind"] == "" {
+		ret["kind"] = string(widgetKind(w))
+	}
+	for k := range ret {
+		if strings.HasSuffix(k, "Html") {
+			delete(ret, k)
+		}
+	}
+	if list, ok := w.(*models.CommunityListWidget); ok {
+		names := make([]string, len(list.Data))
+		for i, sr := range list.Data {
+			names[i] = sr.Name
+		}
+		ret["data"] = names
+	}
+	return ret, nil
+}
+
+// widgetKind returns the kind matching the type of w, so it doesn't need to be set when creating widgets.
+func widgetKind(w models.Widget) models.WidgetKind {
+	switch w.(type) {
+	case *models.TextAreaWidget:
+		return models.WidgetKindTextArea
+	case *models.ButtonWidget:
+		return models.WidgetKindButton
+	case *models.CommunityListWidget:
+		return models.WidgetKindCommunityList
+	case *models.CalendarWidget:
+		return models.WidgetKindCalendar
+	case *models.ImageWidget:
+		return models.WidgetKindImage
+	case *models.RulesWidget:
+		return models.WidgetKindRules
+	case *models.CustomWidget:
+		return models.WidgetKindCustom
+	default:
+		return w.GetKind()
+	}
+}
@@ -0,0 +1,95 @@
+package mira
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+func TestWidgetPayload(t *testing.T) {
+	base := models.WidgetBase{ID: "widget_1", ShortName: "w", Styles: models.WidgetStyles{BackgroundColor: "#fff"}}
+	// baseJSON is what every payload contains: base without its ID.
+	const baseJSON = `"shortName":"w","styles":{"backgroundColor":"#fff","headerColor":""}`
+
+	tests := []struct {
+		name string
+		w    models.Widget
+		want string
+	}{
+		{
+			name: "textarea",
+			w:    &models.TextAreaWidget{WidgetBase: base, Text: "hi", TextHTML: "<p>hi</p>"},
+			want: `{"kind":"textarea",` + baseJSON + `,"text":"hi"}`,
+		},
+		{
+			name: "button",
+			w: &models.ButtonWidget{WidgetBase: base, Description: "d", DescriptionHTML: "<p>d</p>", Buttons: []models.WidgetButton{
+				{Kind: "text", Text: "Wiki", URL: "https://reddit.com/r/w/wiki", Color: "#000"},
+			}},
+			want: `{"kind":"button",` + baseJSON + `,"description":"d","buttons":[{"kind":"text","text":"Wiki","url":"https://reddit.com/r/w/wiki","color":"#000"}]}`,
+		},
+		{
+			name: "community list",
+			w: &models.CommunityListWidget{WidgetBase: base, Data: []models.WidgetCommunity{
+				{Name: "pics", Subscribers: 100, IconURL: "https://example.com/icon.png"},
+				{Name: "aww", IsNSFW: true},
+			}},
+			want: `{"kind":"community-list",` + baseJSON + `,"data":["pics","aww"]}`,
+		},
+		{
+			name: "calendar",
+			w:    &models.CalendarWidget{WidgetBase: base, GoogleCalendarID: "cal@example.com"},
+			want: `{"kind":"calendar",` + baseJSON + `,"googleCalendarId":"cal@example.com","requiresSync":false,"configuration":{"numEvents":0,"showDate":false,"showDescription":false,"showLocation":false,"showTime":false,"showTitle":false}}`,
+		},
+		{
+			name: "image",
+			w:    &models.ImageWidget{WidgetBase: base, Data: []models.WidgetImage{{URL: "https://example.com/a.png", Width: 10, Height: 20}}},
+			want: `{"kind":"image",` + baseJSON + `,"data":[{"url":"https://example.com/a.png","width":10,"height":20}]}`,
+		},
+		{
+			name: "rules",
+			w:    &models.RulesWidget{WidgetBase: base, Display: "compact"},
+			want: `{"kind":"subreddit-rules",` + baseJSON + `,"display":"compact"}`,
+		},
+		{
+			name: "custom",
+			w: &models.CustomWidget{WidgetBase: base, Text: "t", TextHTML: "<p>t</p>", CSS: "p {}", Height: 100,
+				ImageData: []models.WidgetImage{{URL: "https://example.com/a.png", Width: 10, Height: 20, Name: "a"}}},
+			want: `{"kind":"custom",` + baseJSON + `,"text":"t","css":"p {}","height":100,"imageData":[{"url":"https://example.com/a.png","width":10,"height":20,"name":"a"}]}`,
+		},
+		{
+			name: "kind already set",
+			w:    &models.TextAreaWidget{WidgetBase: models.WidgetBase{Kind: models.WidgetKindTextArea, ShortName: "w"}},
+			want: `{"kind":"textarea","shortName":"w","styles":{"backgroundColor":"","headerColor":""},"text":""}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := widgetPayload(tt.w)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Compare the JSON sent to reddit, so numbers & nested values are compared the same way.
+			data, err := json.Marshal(payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, want interface{}
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %s, want %s", data, tt.want)
+			}
+		})
+	}
+
+	if _, err := widgetPayload(&models.UnknownWidget{WidgetBase: models.WidgetBase{Kind: models.WidgetKindMenu}}); err == nil {
+		t.Error("got no error for an unknown widget")
+	}
+}